@@ -0,0 +1,100 @@
+package batches
+
+import (
+	"github.com/mcmathja/funky/pairs"
+)
+
+// Batch2 is like Batch, but produces a key and a value at each step
+// without boxing them into a pairs.Pair. Functions operating on Batch2
+// carry a 2 suffix to distinguish them from their Batch counterparts.
+// Like iter.Seq2, which shares its underlying type, it can be
+// converted to and from a Batch of pairs with Pairs and FromPairs.
+type Batch2[K, V any] func(next func(K, V) bool)
+
+/* Constructors */
+
+// FromMap2 creates a new Batch2 producing
+// the key value pairs of m in an arbitrary order.
+func FromMap2[K comparable, V any](m map[K]V) Batch2[K, V] {
+	return func(next func(K, V) bool) {
+		for k, v := range m {
+			if !next(k, v) {
+				break
+			}
+		}
+	}
+}
+
+// FromPairs creates a new Batch2 producing
+// the left and right side of each pair in b.
+func FromPairs[K, V any](b Batch[pairs.Pair[K, V]]) Batch2[K, V] {
+	return func(next func(K, V) bool) {
+		b(func(kv pairs.Pair[K, V]) bool {
+			return next(kv.Left, kv.Right)
+		})
+	}
+}
+
+/* Operations */
+
+// Filter2 produces the key value pairs in b that pass fn.
+func Filter2[K, V any](b Batch2[K, V], fn func(K, V) bool) Batch2[K, V] {
+	return func(next func(K, V) bool) {
+		b(func(k K, v V) bool {
+			if fn(k, v) {
+				return next(k, v)
+			}
+			return true
+		})
+	}
+}
+
+// Keys2 produces the keys of b.
+func Keys2[K, V any](b Batch2[K, V]) Batch[K] {
+	return func(next func(K) bool) {
+		b(func(k K, _ V) bool {
+			return next(k)
+		})
+	}
+}
+
+// Map2 produces the result of applying fn to each key value pair in b.
+func Map2[K1, V1, K2, V2 any](b Batch2[K1, V1], fn func(K1, V1) (K2, V2)) Batch2[K2, V2] {
+	return func(next func(K2, V2) bool) {
+		b(func(k K1, v V1) bool {
+			return next(fn(k, v))
+		})
+	}
+}
+
+// Pairs converts b into a Batch of pairs.
+func Pairs[K, V any](b Batch2[K, V]) Batch[pairs.Pair[K, V]] {
+	return func(next func(pairs.Pair[K, V]) bool) {
+		b(func(k K, v V) bool {
+			return next(pairs.New(k, v))
+		})
+	}
+}
+
+// Values2 produces the values of b.
+func Values2[K, V any](b Batch2[K, V]) Batch[V] {
+	return func(next func(V) bool) {
+		b(func(_ K, v V) bool {
+			return next(v)
+		})
+	}
+}
+
+/* Converters */
+
+// ToMap2 collects the key value pairs in b into a map.
+// If the same key is repeated twice, the last value wins.
+func ToMap2[K comparable, V any](b Batch2[K, V]) map[K]V {
+	result := make(map[K]V)
+	b(func(k K, v V) bool {
+		result[k] = v
+		return true
+	})
+
+	return result
+}
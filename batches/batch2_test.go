@@ -0,0 +1,98 @@
+package batches_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcmathja/funky/batches"
+	"github.com/mcmathja/funky/pairs"
+)
+
+func TestBatch2(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fn func(b batches.Batch2[int, int]) batches.Batch[pairs.Pair[int, int]]
+		// size is the number of elements in the source batch.
+		size int
+		out  []pairs.Pair[int, int]
+		// limit bounds how many pairs are consumed,
+		// or is zero for no bound.
+		limit int
+		// produced is the number of elements the source must have produced.
+		produced int
+	}{
+		"round trip": {
+			fn:       batches.Pairs[int, int],
+			size:     3,
+			out:      []pairs.Pair[int, int]{pairs.New(0, 0), pairs.New(1, 1), pairs.New(2, 2)},
+			produced: 3,
+		},
+		"filter": {
+			fn: func(b batches.Batch2[int, int]) batches.Batch[pairs.Pair[int, int]] {
+				return batches.Pairs(batches.Filter2(b, func(k, _ int) bool { return k%2 == 1 }))
+			},
+			size:     4,
+			out:      []pairs.Pair[int, int]{pairs.New(1, 1), pairs.New(3, 3)},
+			produced: 4,
+		},
+		"map": {
+			fn: func(b batches.Batch2[int, int]) batches.Batch[pairs.Pair[int, int]] {
+				return batches.Pairs(batches.Map2(b, func(k, v int) (int, int) { return v * 10, k }))
+			},
+			size:     2,
+			out:      []pairs.Pair[int, int]{pairs.New(0, 0), pairs.New(10, 1)},
+			produced: 2,
+		},
+		"stopped early": {
+			fn:       batches.Pairs[int, int],
+			size:     10,
+			limit:    2,
+			out:      []pairs.Pair[int, int]{pairs.New(0, 0), pairs.New(1, 1)},
+			produced: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src, state := tracked(tc.size)
+			b := batches.FromPairs(batches.Map(src, func(i int) pairs.Pair[int, int] {
+				return pairs.New(i, i)
+			}))
+			out := []pairs.Pair[int, int]{}
+			tc.fn(b)(func(kv pairs.Pair[int, int]) bool {
+				out = append(out, kv)
+				return tc.limit == 0 || len(out) < tc.limit
+			})
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if returned, produced := state(); !returned || produced != tc.produced {
+				t.Errorf(`expected the source to return after %d elements, but got %v after %d`, tc.produced, returned, produced)
+			}
+		})
+	}
+}
+
+func TestBatch2KeysAndValues(t *testing.T) {
+	t.Parallel()
+
+	b := batches.FromPairs(batches.New(pairs.New("a", 1), pairs.New("b", 2), pairs.New("a", 3)))
+
+	if out := batches.ToSlice(batches.Keys2(b)); !reflect.DeepEqual(out, []string{"a", "b", "a"}) {
+		t.Errorf(`expected %+v to equal %+v`, out, []string{"a", "b", "a"})
+	}
+	if out := batches.ToSlice(batches.Values2(b)); !reflect.DeepEqual(out, []int{1, 2, 3}) {
+		t.Errorf(`expected %+v to equal %+v`, out, []int{1, 2, 3})
+	}
+	if out := batches.ToMap2(b); !reflect.DeepEqual(out, map[string]int{"a": 3, "b": 2}) {
+		t.Errorf(`expected %+v to equal %+v`, out, map[string]int{"a": 3, "b": 2})
+	}
+	if out := batches.ToMap2(batches.FromMap2(map[string]int{"x": 1})); !reflect.DeepEqual(out, map[string]int{"x": 1}) {
+		t.Errorf(`expected %+v to equal %+v`, out, map[string]int{"x": 1})
+	}
+}
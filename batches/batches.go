@@ -1,6 +1,13 @@
 package batches
 
 import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mcmathja/funky/constraints"
 	"github.com/mcmathja/funky/pairs"
 )
 
@@ -9,6 +16,25 @@ type Batch[T any] func(next func(T) bool)
 
 /* Constructors */
 
+// Cycle creates a new infinite Batch producing the elements
+// of s in order, starting over each time it reaches the end.
+// If s is empty, the Batch is empty.
+func Cycle[T any](s []T) Batch[T] {
+	return func(next func(T) bool) {
+		if len(s) == 0 {
+			return
+		}
+
+		for {
+			for _, ele := range s {
+				if !next(ele) {
+					return
+				}
+			}
+		}
+	}
+}
+
 func FromChan[T any](ch <-chan T) Batch[T] {
 	return func(next func(T) bool) {
 		for ele := range ch {
@@ -19,6 +45,21 @@ func FromChan[T any](ch <-chan T) Batch[T] {
 	}
 }
 
+// FromFunc creates a new Batch producing the elements
+// returned by fn until it reports false. Because fn is
+// called again each time the Batch is run, it typically
+// produces a single pass over some external source.
+func FromFunc[T any](fn func() (T, bool)) Batch[T] {
+	return func(next func(T) bool) {
+		for {
+			ele, ok := fn()
+			if !ok || !next(ele) {
+				return
+			}
+		}
+	}
+}
+
 func FromMap[K comparable, V any](m map[K]V) Batch[pairs.Pair[K, V]] {
 	return func(next func(pairs.Pair[K, V]) bool) {
 		for k, v := range m {
@@ -49,6 +90,24 @@ func FromSlice[T any](s []T) Batch[T] {
 	}
 }
 
+// Generate creates a new Batch producing each element passed to emit
+// by fn. emit reports false once the consumer wants no more elements,
+// at which point fn should return. The sequence is recomputed by
+// calling fn again each time the Batch is run, so it may be infinite.
+func Generate[T any](fn func(emit func(T) bool)) Batch[T] {
+	return Batch[T](fn)
+}
+
+// Iota creates a new infinite Batch producing
+// the integers 0, 1, 2, and so on. It should be
+// bounded with an operation such as Take.
+func Iota() Batch[int] {
+	return func(next func(int) bool) {
+		for num := 0; next(num); num++ {
+		}
+	}
+}
+
 func New[T any](eles ...T) Batch[T] {
 	return func(next func(T) bool) {
 		for _, ele := range eles {
@@ -59,8 +118,94 @@ func New[T any](eles ...T) Batch[T] {
 	}
 }
 
+// Range creates a new Batch producing the values
+// between from (inclusive) and to (exclusive) by step.
+// If step is zero, the Batch is empty.
+func Range[T constraints.Real](from, to, step T) Batch[T] {
+	return func(next func(T) bool) {
+		switch {
+		case step > 0:
+			for num := from; num < to; num += step {
+				if !next(num) {
+					return
+				}
+			}
+		case step < 0:
+			for num := from; num > to; num += step {
+				if !next(num) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Repeat creates a new Batch producing ele n times.
+// If n is zero or negative, the Batch is empty.
+func Repeat[T any](ele T, n int) Batch[T] {
+	return func(next func(T) bool) {
+		for cnt := 0; cnt < n; cnt++ {
+			if !next(ele) {
+				return
+			}
+		}
+	}
+}
+
+// RepeatForever creates a new infinite Batch producing ele.
+// It should be bounded with an operation such as Take or Zip.
+func RepeatForever[T any](ele T) Batch[T] {
+	return func(next func(T) bool) {
+		for next(ele) {
+		}
+	}
+}
+
+// Unfold creates a new Batch by repeatedly applying fn to a state,
+// starting with seed. Each call to fn returns the next element,
+// the next state, and whether the element should be produced;
+// the Batch ends the first time fn returns false.
+func Unfold[T, U any](seed U, fn func(U) (T, U, bool)) Batch[T] {
+	return func(next func(T) bool) {
+		state := seed
+		for {
+			ele, following, ok := fn(state)
+			if !ok || !next(ele) {
+				return
+			}
+			state = following
+		}
+	}
+}
+
 /* Operations */
 
+// All returns true if all of the elements in b
+// satisfy the predicate fn. Otherwise, it returns false.
+// It stops consuming b at the first element that fails fn.
+func All[T any](b Batch[T], fn func(T) bool) bool {
+	result := true
+	b(func(ele T) bool {
+		result = fn(ele)
+		return result
+	})
+
+	return result
+}
+
+// Any returns true if any of the elements in b
+// satisfy the predicate fn. Otherwise, it returns false.
+// It stops consuming b at the first element that passes fn.
+func Any[T any](b Batch[T], fn func(T) bool) bool {
+	result := false
+	b(func(ele T) bool {
+		result = fn(ele)
+		return !result
+	})
+
+	return result
+}
+
 func Append[T any](b Batch[T], ele T) Batch[T] {
 	return func(next func(T) bool) {
 		b(func(in T) bool {
@@ -70,26 +215,122 @@ func Append[T any](b Batch[T], ele T) Batch[T] {
 	}
 }
 
-func Distinct[T comparable](b Batch[T]) Batch[T] {
-	return func(next func(T) bool) {
-		seen := make(map[T]struct{}, 0)
-		b(func(ele T) bool {
-			if _, ok := seen[ele]; !ok {
-				seen[ele] = struct{}{}
-				return next(ele)
-			}
+// Contains checks if b contains ele.
+func Contains[T comparable](b Batch[T], ele T) bool {
+	return Any(b, func(e T) bool {
+		return e == ele
+	})
+}
+
+// ContainsSequence checks if b contains the provided seq
+// as a run of consecutive elements.
+func ContainsSequence[T comparable](b Batch[T], seq []T) bool {
+	if len(seq) == 0 {
+		// All batches contain the empty sequence.
+		return true
+	}
+
+	// Compare seq against a sliding window
+	// of the most recent len(seq) elements.
+	window := make([]T, 0, len(seq))
+	found := false
+	b(func(ele T) bool {
+		if len(window) == len(seq) {
+			copy(window, window[1:])
+			window = window[:len(window)-1]
+		}
+		window = append(window, ele)
+		if len(window) < len(seq) {
 			return true
-		})
+		}
+
+		found = true
+		for idx := range seq {
+			if window[idx] != seq[idx] {
+				found = false
+				break
+			}
+		}
+		return !found
+	})
+
+	return found
+}
+
+// Count counts the number of elements in b
+// that satisfy the predicate fn.
+func Count[T any](b Batch[T], fn func(T) bool) int {
+	cnt := 0
+	b(func(ele T) bool {
+		if fn(ele) {
+			cnt++
+		}
+		return true
+	})
+
+	return cnt
+}
+
+// EvictionPolicy determines which key a bounded Distinct
+// forgets once it is tracking too many keys.
+type EvictionPolicy int
+
+const (
+	// EvictLeastRecent forgets the key that was seen least recently.
+	EvictLeastRecent EvictionPolicy = iota
+	// EvictOldest forgets the key that was first seen longest ago.
+	EvictOldest
+)
+
+// distinctArgs represent optional arguments to Distinct and DistinctBy.
+type distinctArgs struct {
+	// maxSize bounds the number of keys remembered, or zero for no bound.
+	maxSize int
+	// eviction determines which key is forgotten
+	// once more than maxSize keys have been seen.
+	eviction EvictionPolicy
+}
+
+// DistinctOpt represent optional arguments to Distinct and DistinctBy.
+type DistinctOpt func(*distinctArgs)
+
+// DistinctMaxSize is a DistinctOpt that limits the number of keys
+// remembered to n, so that long or infinite batches can be deduplicated
+// in bounded memory. A forgotten key is produced again if it reappears.
+func DistinctMaxSize(n int) DistinctOpt {
+	return func(o *distinctArgs) {
+		o.maxSize = n
 	}
 }
 
-func DistinctBy[T any, U comparable](b Batch[T], fn func(T) U) Batch[T] {
+// DistinctEviction is a DistinctOpt that determines which key is
+// forgotten once DistinctMaxSize is exceeded. By default, the least
+// recently seen key is forgotten.
+func DistinctEviction(policy EvictionPolicy) DistinctOpt {
+	return func(o *distinctArgs) {
+		o.eviction = policy
+	}
+}
+
+// Distinct produces the elements of b, skipping any that were already produced.
+func Distinct[T comparable](b Batch[T], opts ...DistinctOpt) Batch[T] {
+	return DistinctBy(b, func(ele T) T {
+		return ele
+	}, opts...)
+}
+
+// DistinctBy produces the elements of b, skipping any
+// for which fn returns the same key as an earlier element.
+func DistinctBy[T any, U comparable](b Batch[T], fn func(T) U, opts ...DistinctOpt) Batch[T] {
+	args := distinctArgs{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
 	return func(next func(T) bool) {
-		seen := make(map[U]struct{}, 0)
+		seen := newSeenSet[U](args)
 		b(func(ele T) bool {
-			comp := fn(ele)
-			if _, ok := seen[comp]; !ok {
-				seen[comp] = struct{}{}
+			if seen.observe(fn(ele)) {
 				return next(ele)
 			}
 			return true
@@ -126,6 +367,39 @@ func DropWhile[T any](b Batch[T], fn func(T) bool) Batch[T] {
 	}
 }
 
+// Empty checks whether b produces any elements.
+// It stops consuming b after the first element.
+func Empty[T any](b Batch[T]) bool {
+	empty := true
+	b(func(T) bool {
+		empty = false
+		return false
+	})
+
+	return empty
+}
+
+// EnumerateEach calls fn with the index and value of each
+// element in b, stopping early if fn returns false.
+func EnumerateEach[T any](b Batch[T], fn func(int, T) bool) {
+	idx := 0
+	b(func(ele T) bool {
+		cont := fn(idx, ele)
+		idx++
+		return cont
+	})
+}
+
+// Enumerated produces each element of b paired with its index,
+// starting from zero each time the result is run.
+func Enumerated[T any](b Batch[T]) Batch[pairs.Pair[int, T]] {
+	return func(next func(pairs.Pair[int, T]) bool) {
+		EnumerateEach(b, func(idx int, ele T) bool {
+			return next(pairs.New(idx, ele))
+		})
+	}
+}
+
 func Filter[T any](b Batch[T], fn func(T) bool) Batch[T] {
 	return func(next func(T) bool) {
 		b(func(in T) bool {
@@ -137,6 +411,12 @@ func Filter[T any](b Batch[T], fn func(T) bool) Batch[T] {
 	}
 }
 
+// First returns the first element in b, stopping b immediately
+// after producing it. It returns false if b is empty.
+func First[T any](b Batch[T]) (T, bool) {
+	return Nth(b, 1)
+}
+
 func FlatMap[T, U any](b Batch[T], fn func(T) []U) Batch[U] {
 	return func(next func(U) bool) {
 		b(func(in T) bool {
@@ -150,6 +430,12 @@ func FlatMap[T, U any](b Batch[T], fn func(T) []U) Batch[U] {
 	}
 }
 
+// FlatMapBatch is like FlatMap, but fn returns a Batch,
+// so the inner sequences are also produced lazily.
+func FlatMapBatch[T, U any](b Batch[T], fn func(T) Batch[U]) Batch[U] {
+	return FlattenBatch(Map(b, fn))
+}
+
 func Flatten[T any](b Batch[[]T]) Batch[T] {
 	return func(next func(T) bool) {
 		b(func(eles []T) bool {
@@ -163,6 +449,49 @@ func Flatten[T any](b Batch[[]T]) Batch[T] {
 	}
 }
 
+// FlattenBatch produces the elements of each Batch in b in turn.
+func FlattenBatch[T any](b Batch[Batch[T]]) Batch[T] {
+	return func(next func(T) bool) {
+		b(func(inner Batch[T]) bool {
+			cont := true
+			inner(func(ele T) bool {
+				cont = next(ele)
+				return cont
+			})
+			return cont
+		})
+	}
+}
+
+// ForEach calls fn on each element in b.
+func ForEach[T any](b Batch[T], fn func(T)) {
+	b(func(ele T) bool {
+		fn(ele)
+		return true
+	})
+}
+
+// Keys produces the left side of each pair in b.
+func Keys[K, V any](b Batch[pairs.Pair[K, V]]) Batch[K] {
+	return Map(b, func(kv pairs.Pair[K, V]) K {
+		return kv.Left
+	})
+}
+
+// Last returns the final element in b.
+// It returns false if b is empty.
+func Last[T any](b Batch[T]) (T, bool) {
+	var last T
+	found := false
+	b(func(ele T) bool {
+		last = ele
+		found = true
+		return true
+	})
+
+	return last, found
+}
+
 func Map[T, U any](b Batch[T], fn func(T) U) Batch[U] {
 	return func(next func(U) bool) {
 		b(func(in T) bool {
@@ -171,6 +500,185 @@ func Map[T, U any](b Batch[T], fn func(T) U) Batch[U] {
 	}
 }
 
+// Max returns the largest element in b.
+// It returns an error if b is empty.
+func Max[T constraints.Ordered](b Batch[T]) (T, error) {
+	var best T
+	found := false
+	b(func(ele T) bool {
+		if !found || ele > best {
+			best = ele
+			found = true
+		}
+		return true
+	})
+
+	if !found {
+		return best, errors.New("no such element")
+	}
+
+	return best, nil
+}
+
+// Min returns the smallest element in b.
+// It returns an error if b is empty.
+func Min[T constraints.Ordered](b Batch[T]) (T, error) {
+	var best T
+	found := false
+	b(func(ele T) bool {
+		if !found || ele < best {
+			best = ele
+			found = true
+		}
+		return true
+	})
+
+	if !found {
+		return best, errors.New("no such element")
+	}
+
+	return best, nil
+}
+
+// Nth returns the nth element in b, where n=1 is the first element,
+// n=2 is the second, and so on, stopping b immediately after producing it.
+// It returns false if b has fewer than n elements or n is not positive.
+func Nth[T any](b Batch[T], n int) (T, bool) {
+	var result T
+	found := false
+	if n <= 0 {
+		return result, found
+	}
+
+	b(func(ele T) bool {
+		n--
+		if n == 0 {
+			result = ele
+			found = true
+			return false
+		}
+		return true
+	})
+
+	return result, found
+}
+
+// parallelMapArgs represent optional arguments to ParallelMap.
+type parallelMapArgs struct {
+	// ordered indicates whether results must be
+	// produced in the order of their inputs.
+	ordered bool
+}
+
+// ParallelMapOpt represent optional arguments to ParallelMap.
+type ParallelMapOpt func(*parallelMapArgs)
+
+// ParallelMapPreserveOrder is a ParallelMapOpt that indicates
+// results should be produced in the order of their inputs,
+// at the cost of holding back results that finish early.
+func ParallelMapPreserveOrder(o *parallelMapArgs) {
+	o.ordered = true
+}
+
+// ParallelMap produces the result of applying fn to each element of b,
+// spreading the calls to fn across a pool of workers goroutines.
+// By default, results are produced in the order they finish.
+// b is consumed from a separate goroutine, running at most two
+// elements per worker ahead of the consumer. Once the consumer
+// stops early, b and the workers are stopped before returning.
+func ParallelMap[T, U any](b Batch[T], fn func(T) U, workers int, opts ...ParallelMapOpt) Batch[U] {
+	args := parallelMapArgs{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	return func(next func(U) bool) {
+		jobs := make(chan pairs.Pair[int, T])
+		results := make(chan pairs.Pair[int, U])
+		tokens := make(chan struct{}, 2*workers)
+		quit := make(chan struct{})
+		produced := make(chan struct{})
+
+		go func() {
+			defer close(produced)
+			defer close(jobs)
+
+			idx := 0
+			b(func(ele T) bool {
+				select {
+				case tokens <- struct{}{}:
+				case <-quit:
+					return false
+				}
+				select {
+				case jobs <- pairs.New(idx, ele):
+					idx++
+					return true
+				case <-quit:
+					return false
+				}
+			})
+		}()
+
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					select {
+					case results <- pairs.New(job.Left, fn(job.Right)):
+					case <-quit:
+						return
+					}
+				}
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		defer func() {
+			close(quit)
+			for range results {
+			}
+			<-produced
+		}()
+
+		pending := make(map[int]U)
+		nextIdx := 0
+		for res := range results {
+			if !args.ordered {
+				<-tokens
+				if !next(res.Right) {
+					return
+				}
+				continue
+			}
+
+			pending[res.Left] = res.Right
+			for {
+				ele, ok := pending[nextIdx]
+				if !ok {
+					break
+				}
+				delete(pending, nextIdx)
+				nextIdx++
+				<-tokens
+				if !next(ele) {
+					return
+				}
+			}
+		}
+	}
+}
+
 func Prepend[T any](b Batch[T], ele T) Batch[T] {
 	return func(next func(T) bool) {
 		if next(ele) {
@@ -181,6 +689,130 @@ func Prepend[T any](b Batch[T], ele T) Batch[T] {
 	}
 }
 
+// Product returns the product of the elements in b,
+// or 1 if b is empty.
+func Product[T constraints.Numeric](b Batch[T]) T {
+	return Reduce(b, 1, func(product, ele T) T {
+		return product * ele
+	})
+}
+
+// Reduce combines the elements of b into a single value
+// by applying fn to an accumulator and each element in turn,
+// starting from initial.
+func Reduce[T, U any](b Batch[T], initial U, fn func(U, T) U) U {
+	acc := initial
+	b(func(ele T) bool {
+		acc = fn(acc, ele)
+		return true
+	})
+
+	return acc
+}
+
+// Scan is like Reduce, but lazily produces the value of the
+// accumulator after each element, as chans.Reduce does.
+// The accumulator restarts from initial each time the result is run.
+func Scan[T, U any](b Batch[T], initial U, fn func(U, T) U) Batch[U] {
+	return func(next func(U) bool) {
+		acc := initial
+		b(func(ele T) bool {
+			acc = fn(acc, ele)
+			return next(acc)
+		})
+	}
+}
+
+// sortedArgs represent optional arguments to Sorted.
+type sortedArgs struct {
+	// stable indicates whether a stable sort should be performed.
+	stable bool
+}
+
+// SortedOpt represent optional arguments to Sorted.
+type SortedOpt func(*sortedArgs)
+
+// SortedStable is a SortedOpt that indicates
+// a stable sort should be performed.
+func SortedStable(o *sortedArgs) {
+	o.stable = true
+}
+
+// Sorted produces the elements of b in ascending order.
+// Each time the result is run, it first collects every
+// element of b into memory, so b must be finite.
+func Sorted[T constraints.Ordered](b Batch[T], opts ...SortedOpt) Batch[T] {
+	args := sortedArgs{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	var byOpts []SortedByOpt
+	if args.stable {
+		byOpts = append(byOpts, SortedByStable)
+	}
+
+	return SortedBy(b, func(a, b T) bool {
+		return a < b
+	}, byOpts...)
+}
+
+// sortedByArgs represent optional arguments to SortedBy.
+type sortedByArgs struct {
+	// stable indicates whether a stable sort should be performed.
+	stable bool
+}
+
+// SortedByOpt represent optional arguments to SortedBy.
+type SortedByOpt func(*sortedByArgs)
+
+// SortedByStable is a SortedByOpt that indicates
+// a stable sort should be performed.
+func SortedByStable(o *sortedByArgs) {
+	o.stable = true
+}
+
+// SortedBy produces the elements of b in the order determined by less.
+// Each time the result is run, it first collects every
+// element of b into memory, so b must be finite.
+func SortedBy[T any](b Batch[T], less func(a, b T) bool, opts ...SortedByOpt) Batch[T] {
+	args := sortedByArgs{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	return func(next func(T) bool) {
+		var s []T
+		b(func(ele T) bool {
+			s = append(s, ele)
+			return true
+		})
+
+		cmp := func(i, j int) bool {
+			return less(s[i], s[j])
+		}
+		if args.stable {
+			sort.SliceStable(s, cmp)
+		} else {
+			sort.Slice(s, cmp)
+		}
+
+		for _, ele := range s {
+			if !next(ele) {
+				return
+			}
+		}
+	}
+}
+
+// Sum returns the sum of the elements in b.
+func Sum[T constraints.Numeric](b Batch[T]) T {
+	var zero T
+	return Reduce(b, zero, func(sum, ele T) T {
+		return sum + ele
+	})
+}
+
 func Take[T any](b Batch[T], num int) Batch[T] {
 	return func(next func(T) bool) {
 		b(func(in T) bool {
@@ -207,3 +839,342 @@ func TakeWhile[T any](b Batch[T], fn func(T) bool) Batch[T] {
 		})
 	}
 }
+
+// Tee splits b into two batches that each produce every element of b,
+// so that one stream can feed two consumers. b is run only once,
+// from a separate goroutine, and elements that one of the batches has
+// produced but the other has not are buffered until both have. Each
+// returned batch may be run only once, though the two may run
+// concurrently. As soon as either batch finishes, b is stopped, so the
+// other produces only the elements that were already pulled from b.
+// Stopping one batch early therefore never leaves b running, even if
+// the other batch is never run.
+func Tee[T any](b Batch[T]) (Batch[T], Batch[T]) {
+	state := &teeState[T]{}
+	state.next, state.stop = Pull(b)
+
+	return state.side(0), state.side(1)
+}
+
+// Unzip splits a Batch of pairs into a Batch of the left sides
+// and a Batch of the right sides. Each result runs b separately,
+// so b runs twice if both are consumed; use Tee first if b is
+// expensive or can only be run once.
+func Unzip[K, V any](b Batch[pairs.Pair[K, V]]) (Batch[K], Batch[V]) {
+	return Keys(b), Vals(b)
+}
+
+// Vals produces the right side of each pair in b.
+func Vals[K, V any](b Batch[pairs.Pair[K, V]]) Batch[V] {
+	return Map(b, func(kv pairs.Pair[K, V]) V {
+		return kv.Right
+	})
+}
+
+// WithRecover produces the elements of b, recovering any panic raised
+// while producing them, such as by the producer or by a function passed
+// to an earlier stage like Map. A recovered panic is passed to onPanic
+// and ends the Batch early. Panics raised by later stages or the consumer
+// are not recovered, nor are panics on other goroutines, such as within
+// ParallelMap's workers.
+func WithRecover[T any](b Batch[T], onPanic func(any)) Batch[T] {
+	return func(next func(T) bool) {
+		downstream := false
+		defer func() {
+			if r := recover(); r != nil {
+				if downstream {
+					panic(r)
+				}
+				onPanic(r)
+			}
+		}()
+
+		b(func(ele T) bool {
+			downstream = true
+			cont := next(ele)
+			downstream = false
+			return cont
+		})
+	}
+}
+
+// Zip pairs up the elements of a and b by position,
+// stopping once either batch runs out of elements.
+// b is consumed from a separate goroutine, which is
+// stopped once the zipped batch finishes.
+func Zip[T, U any](a Batch[T], b Batch[U]) Batch[pairs.Pair[T, U]] {
+	return func(next func(pairs.Pair[T, U]) bool) {
+		nextB, stop := Pull(b)
+		defer stop()
+
+		a(func(left T) bool {
+			right, ok := nextB()
+			if !ok {
+				return false
+			}
+			return next(pairs.New(left, right))
+		})
+	}
+}
+
+/* Converters */
+
+// CollectSafe collects the elements of b into a slice, recovering
+// any panic raised while producing them as described by WithRecover.
+// It returns the elements collected before the panic along with an
+// error describing it, or every element and nil.
+func CollectSafe[T any](b Batch[T]) ([]T, error) {
+	var failure error
+	result := ToSlice(WithRecover(b, func(r any) {
+		failure = fmt.Errorf("batch panicked: %v", r)
+	}))
+
+	return result, failure
+}
+
+// Pull converts the push-based b into a pull iterator, mirroring iter.Pull.
+// Each call to next produces the next element of b, reporting false once
+// b is exhausted or stop has been called. b runs on a separate goroutine
+// that is started by the first call to next; stop ends it early, and must
+// be called unless next has reported false. next and stop must not be
+// called concurrently.
+func Pull[T any](b Batch[T]) (next func() (T, bool), stop func()) {
+	values := make(chan T)
+	requests := make(chan struct{})
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	started := false
+	stopped := false
+
+	next = func() (T, bool) {
+		var zero T
+		if stopped {
+			return zero, false
+		}
+
+		if !started {
+			started = true
+			go func() {
+				defer close(done)
+				select {
+				case <-requests:
+				case <-quit:
+					return
+				}
+				b(func(ele T) bool {
+					select {
+					case values <- ele:
+					case <-quit:
+						return false
+					}
+					select {
+					case <-requests:
+						return true
+					case <-quit:
+						return false
+					}
+				})
+			}()
+		}
+
+		select {
+		case requests <- struct{}{}:
+		case <-done:
+			return zero, false
+		}
+		select {
+		case ele := <-values:
+			return ele, true
+		case <-done:
+			return zero, false
+		}
+	}
+
+	stop = func() {
+		if stopped {
+			return
+		}
+		stopped = true
+		close(quit)
+		if started {
+			<-done
+		}
+	}
+
+	return next, stop
+}
+
+// ToChan returns a channel that receives the elements of b,
+// produced by a goroutine that closes the channel once b is exhausted.
+// The channel must be read until it closes, or the goroutine will leak.
+func ToChan[T any](b Batch[T]) <-chan T {
+	result := make(chan T)
+	go func() {
+		defer close(result)
+		ForEach(b, func(ele T) {
+			result <- ele
+		})
+	}()
+
+	return result
+}
+
+// ToMap collects the key value pairs in b into a map.
+// If the same key is repeated twice, the last value wins.
+func ToMap[K comparable, V any](b Batch[pairs.Pair[K, V]]) map[K]V {
+	result := make(map[K]V)
+	ForEach(b, func(kv pairs.Pair[K, V]) {
+		result[kv.Left] = kv.Right
+	})
+
+	return result
+}
+
+// ToSet collects the distinct elements of b into a set.
+func ToSet[T comparable](b Batch[T]) map[T]struct{} {
+	result := make(map[T]struct{})
+	ForEach(b, func(ele T) {
+		result[ele] = struct{}{}
+	})
+
+	return result
+}
+
+// ToSlice collects the elements of b into a slice, in order.
+func ToSlice[T any](b Batch[T]) []T {
+	result := make([]T, 0)
+	ForEach(b, func(ele T) {
+		result = append(result, ele)
+	})
+
+	return result
+}
+
+/* Helpers */
+
+// seenSet remembers the keys observed by Distinct and DistinctBy.
+type seenSet[K comparable] struct {
+	args distinctArgs
+	// keys maps each remembered key to its place in order,
+	// or to nil if the set is unbounded.
+	keys map[K]*list.Element
+	// order holds remembered keys from first to last to evict.
+	order *list.List
+}
+
+// newSeenSet creates an empty seenSet configured by args.
+func newSeenSet[K comparable](args distinctArgs) *seenSet[K] {
+	return &seenSet[K]{
+		args:  args,
+		keys:  make(map[K]*list.Element),
+		order: list.New(),
+	}
+}
+
+// observe records key, reporting whether it was not already remembered.
+func (s *seenSet[K]) observe(key K) bool {
+	if elem, ok := s.keys[key]; ok {
+		if elem != nil && s.args.eviction == EvictLeastRecent {
+			s.order.MoveToBack(elem)
+		}
+		return false
+	}
+
+	if s.args.maxSize <= 0 {
+		s.keys[key] = nil
+		return true
+	}
+
+	s.keys[key] = s.order.PushBack(key)
+	if s.order.Len() > s.args.maxSize {
+		delete(s.keys, s.order.Remove(s.order.Front()).(K))
+	}
+
+	return true
+}
+
+// teeState is shared by the two batches returned by Tee.
+type teeState[T any] struct {
+	mu   sync.Mutex
+	next func() (T, bool)
+	stop func()
+	// buffer holds the elements that have been produced
+	// by one side but not yet by the other.
+	buffer []T
+	// offset is the position in b of buffer[0].
+	offset int
+	// pos is the position in b each side will produce next.
+	pos [2]int
+	// finished indicates whether each side has stopped.
+	finished [2]bool
+	// exhausted indicates whether b has run out of elements.
+	exhausted bool
+}
+
+// side returns the Batch for one of the two consumers of a Tee.
+func (s *teeState[T]) side(idx int) Batch[T] {
+	return func(next func(T) bool) {
+		defer s.finish(idx)
+		for {
+			ele, ok := s.get(idx)
+			if !ok || !next(ele) {
+				return
+			}
+		}
+	}
+}
+
+// get returns the next element for the side idx,
+// pulling a new element from b if needed.
+func (s *teeState[T]) get(idx int) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ele T
+	if s.finished[idx] {
+		return ele, false
+	}
+
+	if s.pos[idx] < s.offset+len(s.buffer) {
+		ele = s.buffer[s.pos[idx]-s.offset]
+	} else {
+		if s.exhausted {
+			return ele, false
+		}
+
+		var ok bool
+		ele, ok = s.next()
+		if !ok {
+			s.exhausted = true
+			return ele, false
+		}
+		s.buffer = append(s.buffer, ele)
+	}
+	s.pos[idx]++
+
+	// Discard any elements that no side still needs.
+	low := s.pos[idx]
+	if other := 1 - idx; !s.finished[other] && s.pos[other] < low {
+		low = s.pos[other]
+	}
+	s.buffer = s.buffer[low-s.offset:]
+	s.offset = low
+
+	return ele, true
+}
+
+// finish marks the side idx as stopped and stops b, leaving
+// the other side with only the elements already buffered for it.
+func (s *teeState[T]) finish(idx int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finished[idx] = true
+	if !s.exhausted {
+		s.exhausted = true
+		s.stop()
+	}
+	if s.finished[1-idx] {
+		s.buffer = nil
+	}
+}
@@ -0,0 +1,802 @@
+package batches_test
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcmathja/funky/batches"
+	"github.com/mcmathja/funky/pairs"
+)
+
+// tracked returns a Batch producing 0 through n-1, along with a function
+// reporting whether a run of it has returned and how many elements
+// it has produced.
+func tracked(n int) (batches.Batch[int], func() (bool, int)) {
+	var mu sync.Mutex
+	returned := false
+	produced := 0
+
+	b := func(next func(int) bool) {
+		defer func() {
+			mu.Lock()
+			returned = true
+			mu.Unlock()
+		}()
+
+		for i := 0; i < n; i++ {
+			mu.Lock()
+			produced++
+			mu.Unlock()
+			if !next(i) {
+				return
+			}
+		}
+	}
+
+	return b, func() (bool, int) {
+		mu.Lock()
+		defer mu.Unlock()
+		return returned, produced
+	}
+}
+
+// consume collects the elements of b, stopping b once
+// limit elements have been collected if limit is positive.
+func consume[T any](b batches.Batch[T], limit int) []T {
+	out := []T{}
+	b(func(ele T) bool {
+		out = append(out, ele)
+		return limit <= 0 || len(out) < limit
+	})
+
+	return out
+}
+
+func TestPull(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		size  int
+		pulls int
+		out   []int
+		// produced is the number of elements b must have produced
+		// by the time stop returns.
+		produced int
+	}{
+		"exhausted": {
+			size:     3,
+			pulls:    5,
+			out:      []int{0, 1, 2},
+			produced: 3,
+		},
+		"stopped early": {
+			size:     10,
+			pulls:    2,
+			out:      []int{0, 1},
+			produced: 2,
+		},
+		"stopped before starting": {
+			size:     10,
+			pulls:    0,
+			out:      []int{},
+			produced: 0,
+		},
+		"empty input": {
+			size:     0,
+			pulls:    1,
+			out:      []int{},
+			produced: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b, state := tracked(tc.size)
+			next, stop := batches.Pull(b)
+
+			out := []int{}
+			for i := 0; i < tc.pulls; i++ {
+				ele, ok := next()
+				if !ok {
+					break
+				}
+				out = append(out, ele)
+			}
+			stop()
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			returned, produced := state()
+			if tc.pulls > 0 && !returned {
+				t.Errorf("expected b to have returned once stop returned")
+			}
+			if produced != tc.produced {
+				t.Errorf(`expected %d elements to be produced, but got %d`, tc.produced, produced)
+			}
+
+			if ele, ok := next(); ok {
+				t.Errorf("expected next to report false after stop, but got %+v", ele)
+			}
+			stop()
+		})
+	}
+}
+
+func TestTee(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		size int
+		// limits bounds how many elements each side takes,
+		// or is negative for no bound.
+		limits     [2]int
+		concurrent bool
+		out        [2][]int
+	}{
+		"both exhausted": {
+			size:   4,
+			limits: [2]int{-1, -1},
+			out:    [2][]int{{0, 1, 2, 3}, {0, 1, 2, 3}},
+		},
+		"both exhausted concurrently": {
+			size:       100,
+			limits:     [2]int{-1, -1},
+			concurrent: true,
+			out:        [2][]int{batches.ToSlice(batches.Take(batches.Iota(), 100)), batches.ToSlice(batches.Take(batches.Iota(), 100))},
+		},
+		"first stops early": {
+			size:   4,
+			limits: [2]int{1, -1},
+			out:    [2][]int{{0}, {0}},
+		},
+		"second catches up after first stops early": {
+			size:   10,
+			limits: [2]int{3, -1},
+			out:    [2][]int{{0, 1, 2}, {0, 1, 2}},
+		},
+		"second stops early": {
+			size:   4,
+			limits: [2]int{-1, 2},
+			out:    [2][]int{{0, 1, 2, 3}, {0, 1}},
+		},
+		"both stop early": {
+			size:   10,
+			limits: [2]int{3, 0},
+			out:    [2][]int{{0, 1, 2}, {}},
+		},
+		"empty input": {
+			size:   0,
+			limits: [2]int{-1, -1},
+			out:    [2][]int{{}, {}},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b, state := tracked(tc.size)
+			left, right := batches.Tee(b)
+			sides := [2]batches.Batch[int]{left, right}
+
+			var out [2][]int
+			run := func(idx int) {
+				out[idx] = []int{}
+				if tc.limits[idx] == 0 {
+					sides[idx](func(int) bool { return false })
+					return
+				}
+				sides[idx](func(ele int) bool {
+					out[idx] = append(out[idx], ele)
+					return tc.limits[idx] < 0 || len(out[idx]) < tc.limits[idx]
+				})
+			}
+
+			if tc.concurrent {
+				var wg sync.WaitGroup
+				for idx := range sides {
+					wg.Add(1)
+					go func(idx int) {
+						defer wg.Done()
+						run(idx)
+					}(idx)
+				}
+				wg.Wait()
+			} else {
+				run(0)
+				if returned, _ := state(); !returned {
+					t.Errorf("expected b to have returned once the first side finished")
+				}
+				run(1)
+			}
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if returned, _ := state(); !returned {
+				t.Errorf("expected b to have returned once both sides finished")
+			}
+		})
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	t.Parallel()
+
+	// slow finishes lower inputs later, so that
+	// results complete out of their input order.
+	slow := func(i int) int {
+		time.Sleep(time.Duration(20-i) * time.Millisecond)
+		return i * 10
+	}
+	want := batches.ToSlice(batches.Map(batches.Take(batches.Iota(), 20), func(i int) int { return i * 10 }))
+
+	testCases := map[string]struct {
+		workers int
+		opts    []batches.ParallelMapOpt
+		ordered bool
+	}{
+		"unordered": {
+			workers: 4,
+		},
+		"preserve order": {
+			workers: 4,
+			opts:    []batches.ParallelMapOpt{batches.ParallelMapPreserveOrder},
+			ordered: true,
+		},
+		"preserve order with one worker": {
+			workers: 1,
+			opts:    []batches.ParallelMapOpt{batches.ParallelMapPreserveOrder},
+			ordered: true,
+		},
+		"preserve order with more workers than elements": {
+			workers: 50,
+			opts:    []batches.ParallelMapOpt{batches.ParallelMapPreserveOrder},
+			ordered: true,
+		},
+		"non-positive workers": {
+			workers: 0,
+			opts:    []batches.ParallelMapOpt{batches.ParallelMapPreserveOrder},
+			ordered: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b := batches.Take(batches.Iota(), 20)
+			out := batches.ToSlice(batches.ParallelMap(b, slow, tc.workers, tc.opts...))
+
+			if !tc.ordered {
+				sort.Ints(out)
+			}
+			if !reflect.DeepEqual(out, want) {
+				t.Errorf(`expected %+v to equal %+v`, out, want)
+			}
+		})
+	}
+}
+
+func TestParallelMapStopsEarly(t *testing.T) {
+	t.Parallel()
+
+	b, state := tracked(1000)
+	out := []int{}
+	batches.ParallelMap(b, func(i int) int {
+		return i
+	}, 2, batches.ParallelMapPreserveOrder)(func(ele int) bool {
+		out = append(out, ele)
+		return len(out) < 3
+	})
+
+	if !reflect.DeepEqual(out, []int{0, 1, 2}) {
+		t.Errorf(`expected %+v to equal %+v`, out, []int{0, 1, 2})
+	}
+
+	returned, produced := state()
+	if !returned {
+		t.Errorf("expected b to have returned once the consumer stopped")
+	}
+	// Besides the 3 consumed elements, each of the 2 workers may hold
+	// 2 more, and b may have produced 1 that is waiting for room.
+	if produced > 3+2*2+1 {
+		t.Errorf("expected b to run at most two elements per worker ahead, but it produced %d", produced)
+	}
+}
+
+func TestContainsSequence(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		size int
+		seq  []int
+		out  bool
+		// produced is the number of elements b must have produced.
+		produced int
+	}{
+		"empty sequence": {
+			size:     3,
+			seq:      []int{},
+			out:      true,
+			produced: 0,
+		},
+		"found at the start": {
+			size:     5,
+			seq:      []int{0, 1},
+			out:      true,
+			produced: 2,
+		},
+		"found at the end": {
+			size:     5,
+			seq:      []int{3, 4},
+			out:      true,
+			produced: 5,
+		},
+		"not consecutive": {
+			size:     5,
+			seq:      []int{1, 3},
+			out:      false,
+			produced: 5,
+		},
+		"longer than b": {
+			size:     2,
+			seq:      []int{0, 1, 2},
+			out:      false,
+			produced: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b, state := tracked(tc.size)
+			out := batches.ContainsSequence(b, tc.seq)
+
+			if out != tc.out {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if _, produced := state(); produced != tc.produced {
+				t.Errorf(`expected %d elements to be produced, but got %d`, tc.produced, produced)
+			}
+		})
+	}
+}
+
+func TestZip(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		sizes [2]int
+		// take bounds how many pairs are consumed,
+		// or is negative for no bound.
+		take int
+		out  []pairs.Pair[int, int]
+	}{
+		"equal lengths": {
+			sizes: [2]int{3, 3},
+			take:  -1,
+			out:   []pairs.Pair[int, int]{pairs.New(0, 0), pairs.New(1, 1), pairs.New(2, 2)},
+		},
+		"a shorter": {
+			sizes: [2]int{1, 3},
+			take:  -1,
+			out:   []pairs.Pair[int, int]{pairs.New(0, 0)},
+		},
+		"b shorter": {
+			sizes: [2]int{3, 2},
+			take:  -1,
+			out:   []pairs.Pair[int, int]{pairs.New(0, 0), pairs.New(1, 1)},
+		},
+		"stopped early": {
+			sizes: [2]int{10, 10},
+			take:  2,
+			out:   []pairs.Pair[int, int]{pairs.New(0, 0), pairs.New(1, 1)},
+		},
+		"empty input": {
+			sizes: [2]int{0, 3},
+			take:  -1,
+			out:   []pairs.Pair[int, int]{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			a, stateA := tracked(tc.sizes[0])
+			b, stateB := tracked(tc.sizes[1])
+			zipped := batches.Zip(a, b)
+			if tc.take >= 0 {
+				zipped = batches.Take(zipped, tc.take)
+			}
+			out := batches.ToSlice(zipped)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if returned, _ := stateA(); !returned {
+				t.Errorf("expected a to have returned once the zipped batch finished")
+			}
+			// b is only started once a produces an element.
+			if returned, produced := stateB(); produced > 0 && !returned {
+				t.Errorf("expected b to have returned once the zipped batch finished")
+			}
+		})
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	t.Parallel()
+
+	fib := func(state [2]int) (int, [2]int, bool) {
+		return state[0], [2]int{state[1], state[0] + state[1]}, true
+	}
+
+	testCases := map[string]struct {
+		b     func() batches.Batch[int]
+		limit int
+		// out is the result of each of two consecutive runs of b.
+		out [2][]int
+	}{
+		"generate stopped early": {
+			b: func() batches.Batch[int] {
+				return batches.Generate(func(emit func(int) bool) {
+					for i := 0; emit(i); i++ {
+					}
+				})
+			},
+			limit: 3,
+			out:   [2][]int{{0, 1, 2}, {0, 1, 2}},
+		},
+		"generate returns": {
+			b: func() batches.Batch[int] {
+				return batches.Generate(func(emit func(int) bool) {
+					_ = emit(1) && emit(2)
+				})
+			},
+			out: [2][]int{{1, 2}, {1, 2}},
+		},
+		"from func is a single pass": {
+			b: func() batches.Batch[int] {
+				n := 0
+				return batches.FromFunc(func() (int, bool) {
+					n++
+					return n, n <= 3
+				})
+			},
+			limit: 2,
+			out:   [2][]int{{1, 2}, {3}},
+		},
+		"unfold stopped early": {
+			b: func() batches.Batch[int] {
+				return batches.Unfold([2]int{0, 1}, fib)
+			},
+			limit: 7,
+			out:   [2][]int{{0, 1, 1, 2, 3, 5, 8}, {0, 1, 1, 2, 3, 5, 8}},
+		},
+		"unfold ends when fn reports false": {
+			b: func() batches.Batch[int] {
+				return batches.Unfold(3, func(n int) (int, int, bool) {
+					return n, n - 1, n > 0
+				})
+			},
+			out: [2][]int{{3, 2, 1}, {3, 2, 1}},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b := tc.b()
+			out := [2][]int{consume(b, tc.limit), consume(b, tc.limit)}
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		from, to, step float64
+		limit          int
+		out            []float64
+	}{
+		"ascending": {
+			from: 0, to: 3, step: 1,
+			out: []float64{0, 1, 2},
+		},
+		"descending": {
+			from: 3, to: 0, step: -1,
+			out: []float64{3, 2, 1},
+		},
+		"fractional step": {
+			from: 0, to: 1, step: 0.25,
+			out: []float64{0, 0.25, 0.5, 0.75},
+		},
+		"step overshoots": {
+			from: 0, to: 5, step: 3,
+			out: []float64{0, 3},
+		},
+		"zero step": {
+			from: 0, to: 3, step: 0,
+			out: []float64{},
+		},
+		"step away from to": {
+			from: 0, to: 3, step: -1,
+			out: []float64{},
+		},
+		"stopped early": {
+			from: 0, to: 100, step: 1,
+			limit: 2,
+			out:   []float64{0, 1},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := consume(batches.Range(tc.from, tc.to, tc.step), tc.limit)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+
+	t.Run("iota", func(t *testing.T) {
+		t.Parallel()
+
+		b := batches.Iota()
+		out := [2][]int{consume(b, 3), consume(b, 2)}
+		if !reflect.DeepEqual(out, [2][]int{{0, 1, 2}, {0, 1}}) {
+			t.Errorf(`expected %+v to equal %+v`, out, [2][]int{{0, 1, 2}, {0, 1}})
+		}
+	})
+}
+
+func TestRepeatAndCycle(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		b     batches.Batch[int]
+		limit int
+		out   []int
+	}{
+		"repeat": {
+			b:   batches.Repeat(7, 3),
+			out: []int{7, 7, 7},
+		},
+		"repeat zero times": {
+			b:   batches.Repeat(7, 0),
+			out: []int{},
+		},
+		"repeat negative times": {
+			b:   batches.Repeat(7, -1),
+			out: []int{},
+		},
+		"repeat stopped early": {
+			b:     batches.Repeat(7, 10),
+			limit: 2,
+			out:   []int{7, 7},
+		},
+		"repeat forever": {
+			b:     batches.RepeatForever(7),
+			limit: 4,
+			out:   []int{7, 7, 7, 7},
+		},
+		"cycle wraps around": {
+			b:     batches.Cycle([]int{1, 2, 3}),
+			limit: 7,
+			out:   []int{1, 2, 3, 1, 2, 3, 1},
+		},
+		"cycle stopped mid-pass": {
+			b:     batches.Cycle([]int{1, 2, 3}),
+			limit: 2,
+			out:   []int{1, 2},
+		},
+		"cycle over nothing": {
+			b:     batches.Cycle([]int{}),
+			limit: 3,
+			out:   []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := consume(tc.b, tc.limit)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in    []int
+		opts  []batches.DistinctOpt
+		limit int
+		out   []int
+	}{
+		"unbounded": {
+			in:  []int{1, 2, 1, 3, 1, 2},
+			out: []int{1, 2, 3},
+		},
+		"max size evicts least recently seen": {
+			in:   []int{1, 2, 1, 3, 1, 2},
+			opts: []batches.DistinctOpt{batches.DistinctMaxSize(2)},
+			out:  []int{1, 2, 3, 2},
+		},
+		"max size evicts oldest": {
+			in:   []int{1, 2, 1, 3, 1, 2},
+			opts: []batches.DistinctOpt{batches.DistinctMaxSize(2), batches.DistinctEviction(batches.EvictOldest)},
+			out:  []int{1, 2, 3, 1, 2},
+		},
+		"eviction without max size": {
+			in:   []int{1, 2, 1, 3, 1, 2},
+			opts: []batches.DistinctOpt{batches.DistinctEviction(batches.EvictOldest)},
+			out:  []int{1, 2, 3},
+		},
+		"stopped early": {
+			in:    []int{1, 1, 2, 3, 4},
+			limit: 2,
+			out:   []int{1, 2},
+		},
+		"empty input": {
+			in:  []int{},
+			out: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b := batches.Distinct(batches.FromSlice(tc.in), tc.opts...)
+
+			// Each run starts with nothing seen.
+			for run := 0; run < 2; run++ {
+				if out := consume(b, tc.limit); !reflect.DeepEqual(out, tc.out) {
+					t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+				}
+			}
+		})
+	}
+
+	t.Run("by key", func(t *testing.T) {
+		t.Parallel()
+
+		b := batches.DistinctBy(batches.New("a", "bb", "c", "dd", "eee"), func(s string) int {
+			return len(s)
+		})
+		if out := batches.ToSlice(b); !reflect.DeepEqual(out, []string{"a", "bb", "eee"}) {
+			t.Errorf(`expected %+v to equal %+v`, out, []string{"a", "bb", "eee"})
+		}
+	})
+}
+
+func TestUnzip(t *testing.T) {
+	t.Parallel()
+
+	runs := 0
+	b := batches.Batch[pairs.Pair[int, string]](func(next func(pairs.Pair[int, string]) bool) {
+		runs++
+		_ = next(pairs.New(1, "a")) && next(pairs.New(2, "b")) && next(pairs.New(3, "c"))
+	})
+	left, right := batches.Unzip(b)
+
+	if out := consume(left, 2); !reflect.DeepEqual(out, []int{1, 2}) {
+		t.Errorf(`expected %+v to equal %+v`, out, []int{1, 2})
+	}
+	if out := batches.ToSlice(right); !reflect.DeepEqual(out, []string{"a", "b", "c"}) {
+		t.Errorf(`expected %+v to equal %+v`, out, []string{"a", "b", "c"})
+	}
+	if runs != 2 {
+		t.Errorf(`expected b to run once per result, but it ran %d times`, runs)
+	}
+}
+
+func TestWithRecover(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		b   batches.Batch[int]
+		out []int
+		// recovered is the panic value passed to onPanic, or nil if none.
+		recovered any
+	}{
+		"no panic": {
+			b:   batches.New(1, 2, 3),
+			out: []int{1, 2, 3},
+		},
+		"producer panics": {
+			b: func(next func(int) bool) {
+				_ = next(1) && next(2)
+				panic("boom")
+			},
+			out:       []int{1, 2},
+			recovered: "boom",
+		},
+		"earlier stage panics": {
+			b: batches.Map(batches.New(1, 2, 0, 4), func(i int) int {
+				return 4 / i
+			}),
+			out:       []int{4, 2},
+			recovered: "runtime error: integer divide by zero",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var recovered any
+			out := batches.ToSlice(batches.WithRecover(tc.b, func(r any) {
+				recovered = r
+				if err, ok := r.(error); ok {
+					recovered = err.Error()
+				}
+			}))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if recovered != tc.recovered {
+				t.Errorf(`expected %+v to be recovered, but got %+v`, tc.recovered, recovered)
+			}
+
+			safe, err := batches.CollectSafe(tc.b)
+			if !reflect.DeepEqual(safe, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, safe, tc.out)
+			}
+			if (err != nil) != (tc.recovered != nil) {
+				t.Errorf(`expected an error only if b panics, but got %v`, err)
+			}
+		})
+	}
+
+	t.Run("consumer panics are not recovered", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			if r := recover(); r != "consumer" {
+				t.Errorf(`expected the consumer's panic to propagate, but recovered %+v`, r)
+			}
+		}()
+
+		batches.WithRecover(batches.New(1, 2), func(r any) {
+			t.Errorf(`expected onPanic not to be called, but it received %+v`, r)
+		})(func(int) bool {
+			panic("consumer")
+		})
+	})
+}
@@ -0,0 +1,124 @@
+package batches
+
+// Pipeline wraps a Batch so that a long lazy pipeline can be
+// written as a chain of method calls rather than nested function calls.
+// Every stage delegates to the package level function of the same name.
+//
+// Only operations that preserve the element type can be methods.
+// Operations that change it are provided as Pipe-prefixed functions,
+// such as PipeMap and PipeFlatMap.
+type Pipeline[T any] struct {
+	b Batch[T]
+}
+
+// Pipe creates a new Pipeline producing the elements of b.
+func Pipe[T any](b Batch[T]) Pipeline[T] {
+	return Pipeline[T]{
+		b: b,
+	}
+}
+
+// Batch returns the Batch produced by the final stage of p.
+func (p Pipeline[T]) Batch() Batch[T] {
+	return p.b
+}
+
+/* Stages */
+
+func (p Pipeline[T]) Append(ele T) Pipeline[T] {
+	return Pipe(Append(p.b, ele))
+}
+
+func (p Pipeline[T]) Drop(num int) Pipeline[T] {
+	return Pipe(Drop(p.b, num))
+}
+
+func (p Pipeline[T]) DropWhile(fn func(T) bool) Pipeline[T] {
+	return Pipe(DropWhile(p.b, fn))
+}
+
+func (p Pipeline[T]) Filter(fn func(T) bool) Pipeline[T] {
+	return Pipe(Filter(p.b, fn))
+}
+
+func (p Pipeline[T]) Prepend(ele T) Pipeline[T] {
+	return Pipe(Prepend(p.b, ele))
+}
+
+func (p Pipeline[T]) SortedBy(less func(a, b T) bool, opts ...SortedByOpt) Pipeline[T] {
+	return Pipe(SortedBy(p.b, less, opts...))
+}
+
+func (p Pipeline[T]) Take(num int) Pipeline[T] {
+	return Pipe(Take(p.b, num))
+}
+
+func (p Pipeline[T]) TakeWhile(fn func(T) bool) Pipeline[T] {
+	return Pipe(TakeWhile(p.b, fn))
+}
+
+/* Terminals */
+
+func (p Pipeline[T]) All(fn func(T) bool) bool {
+	return All(p.b, fn)
+}
+
+func (p Pipeline[T]) Any(fn func(T) bool) bool {
+	return Any(p.b, fn)
+}
+
+// Collect collects the elements produced by p into a slice.
+func (p Pipeline[T]) Collect() []T {
+	return ToSlice(p.b)
+}
+
+func (p Pipeline[T]) Count(fn func(T) bool) int {
+	return Count(p.b, fn)
+}
+
+func (p Pipeline[T]) Empty() bool {
+	return Empty(p.b)
+}
+
+func (p Pipeline[T]) First() (T, bool) {
+	return First(p.b)
+}
+
+func (p Pipeline[T]) ForEach(fn func(T)) {
+	ForEach(p.b, fn)
+}
+
+func (p Pipeline[T]) Last() (T, bool) {
+	return Last(p.b)
+}
+
+func (p Pipeline[T]) Nth(n int) (T, bool) {
+	return Nth(p.b, n)
+}
+
+/* Type-changing stages */
+
+// PipeFlatMap appends a FlatMap stage to p.
+func PipeFlatMap[T, U any](p Pipeline[T], fn func(T) []U) Pipeline[U] {
+	return Pipe(FlatMap(p.b, fn))
+}
+
+// PipeFlatMapBatch appends a FlatMapBatch stage to p.
+func PipeFlatMapBatch[T, U any](p Pipeline[T], fn func(T) Batch[U]) Pipeline[U] {
+	return Pipe(FlatMapBatch(p.b, fn))
+}
+
+// PipeMap appends a Map stage to p.
+func PipeMap[T, U any](p Pipeline[T], fn func(T) U) Pipeline[U] {
+	return Pipe(Map(p.b, fn))
+}
+
+// PipeParallelMap appends a ParallelMap stage to p.
+func PipeParallelMap[T, U any](p Pipeline[T], fn func(T) U, workers int, opts ...ParallelMapOpt) Pipeline[U] {
+	return Pipe(ParallelMap(p.b, fn, workers, opts...))
+}
+
+// PipeScan appends a Scan stage to p.
+func PipeScan[T, U any](p Pipeline[T], initial U, fn func(U, T) U) Pipeline[U] {
+	return Pipe(Scan(p.b, initial, fn))
+}
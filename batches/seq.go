@@ -0,0 +1,40 @@
+//go:build go1.23
+
+package batches
+
+import (
+	"iter"
+
+	"github.com/mcmathja/funky/pairs"
+)
+
+// FromSeq creates a new Batch producing the elements of seq.
+func FromSeq[T any](seq iter.Seq[T]) Batch[T] {
+	return Batch[T](seq)
+}
+
+// FromSeq2 creates a new Batch producing
+// the key value pairs of seq as pairs.
+func FromSeq2[K, V any](seq iter.Seq2[K, V]) Batch[pairs.Pair[K, V]] {
+	return func(next func(pairs.Pair[K, V]) bool) {
+		seq(func(k K, v V) bool {
+			return next(pairs.New(k, v))
+		})
+	}
+}
+
+// ToSeq converts b into an iter.Seq, so that
+// it can be used in a range-over-func loop.
+func ToSeq[T any](b Batch[T]) iter.Seq[T] {
+	return iter.Seq[T](b)
+}
+
+// ToSeq2 converts a Batch of pairs into an iter.Seq2
+// producing the left and right side of each pair.
+func ToSeq2[K, V any](b Batch[pairs.Pair[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		b(func(kv pairs.Pair[K, V]) bool {
+			return yield(kv.Left, kv.Right)
+		})
+	}
+}
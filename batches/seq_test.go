@@ -0,0 +1,102 @@
+//go:build go1.23
+
+package batches_test
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/mcmathja/funky/batches"
+	"github.com/mcmathja/funky/pairs"
+)
+
+func TestSeq(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		size int
+		// stopAt is the element at which the
+		// range loop breaks, or negative for none.
+		stopAt int
+		out    []int
+		// produced is the number of elements b must have produced.
+		produced int
+	}{
+		"exhausted": {
+			size:     3,
+			stopAt:   -1,
+			out:      []int{0, 1, 2},
+			produced: 3,
+		},
+		"break early": {
+			size:     10,
+			stopAt:   1,
+			out:      []int{0, 1},
+			produced: 2,
+		},
+		"empty input": {
+			size:     0,
+			stopAt:   -1,
+			out:      []int{},
+			produced: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b, state := tracked(tc.size)
+			out := []int{}
+			for ele := range batches.ToSeq(b) {
+				out = append(out, ele)
+				if ele == tc.stopAt {
+					break
+				}
+			}
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if returned, produced := state(); !returned || produced != tc.produced {
+				t.Errorf(`expected b to return after %d elements, but got %v after %d`, tc.produced, returned, produced)
+			}
+
+			roundTrip := batches.ToSlice(batches.FromSeq(slices.Values(tc.out)))
+			if !reflect.DeepEqual(roundTrip, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, roundTrip, tc.out)
+			}
+		})
+	}
+}
+
+func TestSeq2(t *testing.T) {
+	t.Parallel()
+
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	out := batches.ToSlice(batches.FromSeq2(maps.All(m)))
+	sort.Slice(out, func(i, j int) bool { return out[i].Left < out[j].Left })
+	want := []pairs.Pair[string, int]{pairs.New("a", 1), pairs.New("b", 2), pairs.New("c", 3)}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf(`expected %+v to equal %+v`, out, want)
+	}
+
+	cnt := 0
+	for k, v := range batches.ToSeq2(batches.FromSlice(want)) {
+		if m[k] != v {
+			t.Errorf(`expected %s to map to %d, but got %d`, k, m[k], v)
+		}
+		cnt++
+		if cnt == 2 {
+			break
+		}
+	}
+	if cnt != 2 {
+		t.Errorf(`expected the loop to stop after 2 pairs, but it ran %d times`, cnt)
+	}
+}
@@ -0,0 +1,95 @@
+package batches
+
+// TryBatch is like Batch, but each step may produce an error
+// instead of an element, allowing fallible producers such as
+// file readers and decoders to take part in lazy pipelines.
+// Operations forward errors unchanged, leaving it to the
+// consumer to decide whether to stop at the first one.
+type TryBatch[T any] func(next func(T, error) bool)
+
+/* Constructors */
+
+// Try creates a new TryBatch producing
+// the elements of b without any errors.
+func Try[T any](b Batch[T]) TryBatch[T] {
+	return func(next func(T, error) bool) {
+		b(func(ele T) bool {
+			return next(ele, nil)
+		})
+	}
+}
+
+// TryFromFunc creates a new TryBatch producing the elements
+// returned by fn until it reports false or returns an error.
+// The error, if any, is produced as the final step.
+func TryFromFunc[T any](fn func() (T, bool, error)) TryBatch[T] {
+	return func(next func(T, error) bool) {
+		for {
+			ele, ok, err := fn()
+			if err != nil {
+				next(ele, err)
+				return
+			}
+			if !ok || !next(ele, nil) {
+				return
+			}
+		}
+	}
+}
+
+/* Operations */
+
+// TryFilter produces the elements of b that pass fn.
+// If fn returns an error, the error is produced in place of the element.
+func TryFilter[T any](b TryBatch[T], fn func(T) (bool, error)) TryBatch[T] {
+	return func(next func(T, error) bool) {
+		b(func(ele T, err error) bool {
+			if err != nil {
+				return next(ele, err)
+			}
+
+			ok, err := fn(ele)
+			if err != nil {
+				return next(ele, err)
+			}
+			if ok {
+				return next(ele, nil)
+			}
+			return true
+		})
+	}
+}
+
+// TryMap produces the result of applying fn to each element of b.
+// If fn returns an error, the error is produced in place of the result.
+func TryMap[T, U any](b TryBatch[T], fn func(T) (U, error)) TryBatch[U] {
+	return func(next func(U, error) bool) {
+		b(func(ele T, err error) bool {
+			if err != nil {
+				var zero U
+				return next(zero, err)
+			}
+			return next(fn(ele))
+		})
+	}
+}
+
+/* Converters */
+
+// CollectOrErr collects the elements of b into a slice, stopping
+// at the first error. It returns the elements collected up to that
+// point along with the error, or every element and nil.
+func CollectOrErr[T any](b TryBatch[T]) ([]T, error) {
+	result := make([]T, 0)
+	var failure error
+	b(func(ele T, err error) bool {
+		if err != nil {
+			failure = err
+			return false
+		}
+		result = append(result, ele)
+		return true
+	})
+
+	return result, failure
+}
@@ -0,0 +1,149 @@
+package batches_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mcmathja/funky/batches"
+)
+
+func TestTryBatch(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	failAt := func(n int) func(int) (int, error) {
+		return func(i int) (int, error) {
+			if i == n {
+				return 0, errBoom
+			}
+			return i * 10, nil
+		}
+	}
+
+	testCases := map[string]struct {
+		fn   func(b batches.TryBatch[int]) batches.TryBatch[int]
+		size int
+		out  []int
+		err  error
+		// produced is the number of elements the source must have produced.
+		produced int
+	}{
+		"no errors": {
+			fn:       func(b batches.TryBatch[int]) batches.TryBatch[int] { return b },
+			size:     3,
+			out:      []int{0, 1, 2},
+			produced: 3,
+		},
+		"map error stops collection": {
+			fn: func(b batches.TryBatch[int]) batches.TryBatch[int] {
+				return batches.TryMap(b, failAt(2))
+			},
+			size:     10,
+			out:      []int{0, 10},
+			err:      errBoom,
+			produced: 3,
+		},
+		"filter error stops collection": {
+			fn: func(b batches.TryBatch[int]) batches.TryBatch[int] {
+				return batches.TryFilter(b, func(i int) (bool, error) {
+					if i == 3 {
+						return false, errBoom
+					}
+					return i%2 == 0, nil
+				})
+			},
+			size:     10,
+			out:      []int{0, 2},
+			err:      errBoom,
+			produced: 4,
+		},
+		"errors pass through later stages": {
+			fn: func(b batches.TryBatch[int]) batches.TryBatch[int] {
+				filtered := batches.TryFilter(batches.TryMap(b, failAt(1)), func(int) (bool, error) {
+					return false, nil
+				})
+				return batches.TryMap(filtered, failAt(-1))
+			},
+			size:     10,
+			out:      []int{},
+			err:      errBoom,
+			produced: 2,
+		},
+		"empty input": {
+			fn:       func(b batches.TryBatch[int]) batches.TryBatch[int] { return b },
+			size:     0,
+			out:      []int{},
+			produced: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b, state := tracked(tc.size)
+			out, err := batches.CollectOrErr(tc.fn(batches.Try(b)))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf(`expected %v to be %v`, err, tc.err)
+			}
+			if returned, produced := state(); !returned || produced != tc.produced {
+				t.Errorf(`expected b to return after %d elements, but got %v after %d`, tc.produced, returned, produced)
+			}
+		})
+	}
+}
+
+func TestTryFromFunc(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	testCases := map[string]struct {
+		steps []error
+		out   []int
+		err   error
+	}{
+		"exhausted": {
+			steps: []error{nil, nil},
+			out:   []int{0, 1},
+		},
+		"error is the final step": {
+			steps: []error{nil, errBoom, nil},
+			out:   []int{0},
+			err:   errBoom,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+			b := batches.TryFromFunc(func() (int, bool, error) {
+				if calls == len(tc.steps) {
+					return 0, false, nil
+				}
+				calls++
+				return calls - 1, true, tc.steps[calls-1]
+			})
+			out, err := batches.CollectOrErr(b)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf(`expected %v to be %v`, err, tc.err)
+			}
+			if tc.err != nil && calls != len(tc.out)+1 {
+				t.Errorf(`expected fn to stop being called after the error, but it was called %d times`, calls)
+			}
+		})
+	}
+}
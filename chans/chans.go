@@ -1,69 +1,386 @@
 // chans provides generic convenience functions for working with channels.
+//
+// Functions that return channels spawn goroutines to produce their results.
+// Those goroutines run until their inputs are exhausted, so a consumer
+// that stops reading early would otherwise leak them. Passing WithContext
+// ties each goroutine to a context, which should be cancelled once the
+// consumer is done with the output.
 package chans
 
 import (
+	"container/heap"
+	"container/list"
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/mcmathja/funky/constraints"
 	"github.com/mcmathja/funky/pairs"
 )
 
+// chanArgs represent optional arguments to channel operations.
+type chanArgs struct {
+	// ctx governs the lifetime of any goroutines
+	// spawned by the operation.
+	ctx context.Context
+	// capacity is the buffer size of any output channels.
+	capacity int
+	// ordered indicates whether concurrent operations
+	// must emit results in the order of their inputs.
+	ordered bool
+}
+
+// ChanOpt represent optional arguments to channel operations.
+type ChanOpt func(*chanArgs)
+
+// WithContext is a ChanOpt that stops the goroutines
+// spawned by an operation once ctx is cancelled,
+// closing the output channels.
+func WithContext(ctx context.Context) ChanOpt {
+	return func(args *chanArgs) {
+		args.ctx = ctx
+	}
+}
+
+// WithCapacity is a ChanOpt that gives the output channels
+// of an operation a buffer of size n, allowing producers
+// to run ahead of their consumers. By default, output
+// channels are unbuffered.
+func WithCapacity(n int) ChanOpt {
+	return func(args *chanArgs) {
+		if n < 0 {
+			n = 0
+		}
+		args.capacity = n
+	}
+}
+
+// PreserveOrder is a ChanOpt that makes concurrent operations
+// emit their results in the same order as the corresponding inputs,
+// at the cost of holding back results that finish early.
+// It has no effect on sequential operations, which always preserve order.
+func PreserveOrder(args *chanArgs) {
+	args.ordered = true
+}
+
+// distinctArgs represent optional arguments to Distinct and DistinctBy.
+type distinctArgs struct {
+	chanArgs
+	// maxSize bounds the number of keys remembered, or zero for no bound.
+	maxSize int
+	// ttl bounds how long a key is remembered, or zero for no bound.
+	ttl time.Duration
+}
+
+// DistinctOpt represent optional arguments to Distinct and DistinctBy.
+// Every ChanOpt is also a DistinctOpt.
+type DistinctOpt interface {
+	applyDistinct(*distinctArgs)
+}
+
+// distinctOpt is a DistinctOpt that only applies to Distinct and DistinctBy.
+type distinctOpt func(*distinctArgs)
+
+func (opt distinctOpt) applyDistinct(args *distinctArgs) {
+	opt(args)
+}
+
+func (opt ChanOpt) applyDistinct(args *distinctArgs) {
+	opt(&args.chanArgs)
+}
+
+// DistinctMaxSize is a DistinctOpt that limits Distinct and DistinctBy
+// to remembering the n most recently seen keys, evicting the
+// least recently seen key when the limit is exceeded.
+// An evicted key is emitted again if it reappears.
+func DistinctMaxSize(n int) DistinctOpt {
+	return distinctOpt(func(args *distinctArgs) {
+		args.maxSize = n
+	})
+}
+
+// DistinctTTL is a DistinctOpt that makes Distinct and DistinctBy
+// forget a key once it has not been seen for d.
+// A forgotten key is emitted again if it reappears.
+func DistinctTTL(d time.Duration) DistinctOpt {
+	return distinctOpt(func(args *distinctArgs) {
+		args.ttl = d
+	})
+}
+
+// randomArgs represent optional arguments to SampleRate.
+type randomArgs struct {
+	chanArgs
+	// random produces values in [0, 1).
+	random func() float64
+}
+
+// RandomOpt represent optional arguments to SampleRate.
+// Every ChanOpt is also a RandomOpt.
+type RandomOpt interface {
+	applyRandom(*randomArgs)
+}
+
+// randomOpt is a RandomOpt that only applies to SampleRate.
+type randomOpt func(*randomArgs)
+
+func (opt randomOpt) applyRandom(args *randomArgs) {
+	opt(args)
+}
+
+func (opt ChanOpt) applyRandom(args *randomArgs) {
+	opt(&args.chanArgs)
+}
+
+// WithRandom is a RandomOpt that makes SampleRate draw from fn,
+// which must return values in the range [0, 1).
+// By default, math/rand.Float64 is used.
+func WithRandom(fn func() float64) RandomOpt {
+	return randomOpt(func(args *randomArgs) {
+		args.random = fn
+	})
+}
+
+// distributeArgs represent optional arguments to Distribute.
+type distributeArgs struct {
+	chanArgs
+	// leastLoaded indicates whether each element is sent to
+	// the output with the fewest queued elements.
+	leastLoaded bool
+	// onDepth observes the queue depths of the outputs.
+	onDepth func(idx, depth int)
+}
+
+// DistributeOpt represent optional arguments to Distribute.
+// Every ChanOpt is also a DistributeOpt.
+type DistributeOpt interface {
+	applyDistribute(*distributeArgs)
+}
+
+// distributeOpt is a DistributeOpt that only applies to Distribute.
+type distributeOpt func(*distributeArgs)
+
+func (opt distributeOpt) applyDistribute(args *distributeArgs) {
+	opt(args)
+}
+
+func (opt ChanOpt) applyDistribute(args *distributeArgs) {
+	opt(&args.chanArgs)
+}
+
+// DistributeLeastLoaded is a DistributeOpt that makes Distribute send
+// each element to the output with the fewest elements queued for its
+// consumer, rather than to whichever output is ready first. Each
+// output queues at most the capacity given through WithCapacity,
+// or a single element by default, and Distribute waits for room
+// once every queue is full.
+func DistributeLeastLoaded() DistributeOpt {
+	return distributeOpt(func(args *distributeArgs) {
+		args.leastLoaded = true
+	})
+}
+
+// DistributeOnDepth is a DistributeOpt that makes Distribute call fn
+// with the index of an output and its new queue depth whenever an
+// element is queued for that output or received by its consumer.
+// Only a least-loaded Distribute tracks its queues, so this option
+// implies DistributeLeastLoaded. fn may be called from multiple
+// goroutines and should return quickly.
+func DistributeOnDepth(fn func(idx, depth int)) DistributeOpt {
+	return distributeOpt(func(args *distributeArgs) {
+		args.leastLoaded = true
+		args.onDepth = fn
+	})
+}
+
+// bridgeArgs represent optional arguments to Bridge.
+type bridgeArgs struct {
+	chanArgs
+	// drainOnCancel indicates whether to drain the inputs
+	// in the background once cancelled.
+	drainOnCancel bool
+}
+
+// BridgeOpt represent optional arguments to Bridge.
+// Every ChanOpt is also a BridgeOpt.
+type BridgeOpt interface {
+	applyBridge(*bridgeArgs)
+}
+
+// bridgeOpt is a BridgeOpt that only applies to Bridge.
+type bridgeOpt func(*bridgeArgs)
+
+func (opt bridgeOpt) applyBridge(args *bridgeArgs) {
+	opt(args)
+}
+
+func (opt ChanOpt) applyBridge(args *bridgeArgs) {
+	opt(&args.chanArgs)
+}
+
+// DrainOnCancel is a BridgeOpt that makes Bridge, once its context is
+// cancelled, keep receiving and discarding elements from the inner
+// channel it was reading and from every inner channel still to arrive,
+// until they close. This unblocks producers that were not built to
+// observe the context, at the cost of running until they finish.
+func DrainOnCancel() BridgeOpt {
+	return bridgeOpt(func(args *bridgeArgs) {
+		args.drainOnCancel = true
+	})
+}
+
+// lookupArgs represent optional arguments to blocking lookups
+// such as First and Last.
+type lookupArgs struct {
+	chanArgs
+	// timeout bounds how long to wait for a result, or zero for no bound.
+	timeout time.Duration
+}
+
+// LookupOpt represent optional arguments to blocking lookups
+// such as First and Last. Every ChanOpt is also a LookupOpt.
+type LookupOpt interface {
+	applyLookup(*lookupArgs)
+}
+
+// lookupOpt is a LookupOpt that only applies to blocking lookups.
+type lookupOpt func(*lookupArgs)
+
+func (opt lookupOpt) applyLookup(args *lookupArgs) {
+	opt(args)
+}
+
+func (opt ChanOpt) applyLookup(args *lookupArgs) {
+	opt(&args.chanArgs)
+}
+
+// WithTimeout is a LookupOpt that makes blocking lookups such as
+// First and Last give up and report false once d has elapsed.
+func WithTimeout(d time.Duration) LookupOpt {
+	return lookupOpt(func(args *lookupArgs) {
+		args.timeout = d
+	})
+}
+
+// OverflowStrategy determines what happens when
+// an element arrives for a buffer that is already full.
+type OverflowStrategy int
+
+const (
+	// OverflowBlock waits until there is room in the buffer.
+	OverflowBlock OverflowStrategy = iota
+	// OverflowDropNewest discards the arriving element.
+	OverflowDropNewest
+	// OverflowDropOldest discards the oldest buffered element
+	// to make room for the arriving element.
+	OverflowDropOldest
+)
+
 /* Constructors */
 
-func FromBatch[T any](b func(func(T))) <-chan T {
-	result := make(chan T)
+func FromBatch[T any](b func(func(T)), opts ...ChanOpt) <-chan T {
+	args := newChanArgs(opts)
+	result := make(chan T, args.capacity)
 	go func() {
 		defer close(result)
+		done := false
 		b(func(ele T) {
-			result <- ele
+			if !done {
+				done = !send(args.ctx, result, ele)
+			}
 		})
 	}()
 
 	return result
 }
 
-func FromFunc[Elem any](fn func() Elem) <-chan Elem {
-	result := make(chan Elem)
+func FromFunc[Elem any](fn func() Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
-		result <- fn()
+		send(args.ctx, result, fn())
 	}()
 
 	return result
 }
 
-func FromMap[K comparable, V any](m map[K]V) <-chan pairs.Pair[K, V] {
-	result := make(chan pairs.Pair[K, V])
+func FromMap[K comparable, V any](m map[K]V, opts ...ChanOpt) <-chan pairs.Pair[K, V] {
+	args := newChanArgs(opts)
+	result := make(chan pairs.Pair[K, V], args.capacity)
 
 	go func() {
 		defer close(result)
 		for key, value := range m {
-			result <- pairs.New(key, value)
+			if !send(args.ctx, result, pairs.New(key, value)) {
+				return
+			}
 		}
 	}()
 
 	return result
 }
 
-func FromSet[T comparable](m map[T]struct{}) <-chan T {
-	result := make(chan T)
+func FromSet[T comparable](m map[T]struct{}, opts ...ChanOpt) <-chan T {
+	args := newChanArgs(opts)
+	result := make(chan T, args.capacity)
 
 	go func() {
 		defer close(result)
 		for ele := range m {
-			result <- ele
+			if !send(args.ctx, result, ele) {
+				return
+			}
 		}
 	}()
 
 	return result
 }
 
-func FromSlice[T comparable](m []T) <-chan T {
-	result := make(chan T)
+// FromSlice behaves like New,
+// but accepts its elements as a slice alongside ChanOpts.
+func FromSlice[T any](m []T, opts ...ChanOpt) <-chan T {
+	args := newChanArgs(opts)
+	result := make(chan T, args.capacity)
 
 	go func() {
 		defer close(result)
 		for _, ele := range m {
-			result <- ele
+			if !send(args.ctx, result, ele) {
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// Interval creates a new channel that emits an incrementing
+// counter, starting from zero, each time d elapses.
+// Ticks are dropped if the consumer falls behind.
+// The channel never closes on its own, so callers should
+// provide WithContext and cancel it when done.
+func Interval(d time.Duration, opts ...ChanOpt) <-chan int {
+	args := newChanArgs(opts)
+	result := make(chan int, args.capacity)
+	go func() {
+		defer close(result)
+
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+		for cnt := 0; ; cnt++ {
+			select {
+			case <-ticker.C:
+			case <-args.ctx.Done():
+				return
+			}
+
+			if !send(args.ctx, result, cnt) {
+				return
+			}
 		}
 	}()
 
@@ -71,12 +388,27 @@ func FromSlice[T comparable](m []T) <-chan T {
 }
 
 // New creates a new channel from a sequence of elements.
+// To stop its goroutine early or buffer its output,
+// pass the elements to FromSlice alongside ChanOpts.
 func New[Elem any](eles ...Elem) <-chan Elem {
-	result := make(chan Elem)
+	return FromSlice(eles)
+}
+
+// Timer creates a new channel that emits a single value
+// once d elapses and then closes. The value is buffered,
+// so the timer never blocks waiting for a consumer.
+func Timer(d time.Duration, opts ...ChanOpt) <-chan struct{} {
+	args := newChanArgs(opts)
+	result := make(chan struct{}, 1)
 	go func() {
 		defer close(result)
-		for _, ele := range eles {
-			result <- ele
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			result <- struct{}{}
+		case <-args.ctx.Done():
 		}
 	}()
 
@@ -105,29 +437,88 @@ func Any[Elem any](ch <-chan Elem, fn func(Elem) bool) bool {
 	return false
 }
 
-func Append[Elem any](ch <-chan Elem, ele Elem) <-chan Elem {
-	result := make(chan Elem)
+func Append[Elem any](ch <-chan Elem, ele Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		for {
+			e, ok := recv(args.ctx, ch)
+			if !ok {
+				break
+			}
+			if !send(args.ctx, result, e) {
+				return
+			}
+		}
+		if args.ctx.Err() == nil {
+			send(args.ctx, result, ele)
+		}
+	}()
+
+	return result
+}
+
+// Bridge emits the elements of each channel received from chs in turn,
+// reading an inner channel to completion before moving on to the next,
+// so elements are emitted strictly in order. It stops and closes its
+// output as soon as ctx is cancelled, abandoning the inner channel it was
+// reading and any still to arrive; use DrainOnCancel to drain them instead.
+// ctx takes precedence over any context given through WithContext.
+func Bridge[Elem any](ctx context.Context, chs <-chan <-chan Elem, opts ...BridgeOpt) <-chan Elem {
+	args := newBridgeArgs(opts)
+	args.ctx = ctx
+
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
 
-		for e := range ch {
-			result <- e
+		var inner <-chan Elem
+		defer func() {
+			if args.drainOnCancel && ctx.Err() != nil {
+				go func(inner <-chan Elem) {
+					if inner != nil {
+						Drain(inner)
+					}
+					for inner := range chs {
+						Drain(inner)
+					}
+				}(inner)
+			}
+		}()
+
+		for {
+			next, ok := recv(ctx, chs)
+			if !ok {
+				return
+			}
+			inner = next
+			for {
+				ele, ok := recv(ctx, inner)
+				if !ok {
+					break
+				}
+				if !send(ctx, result, ele) {
+					return
+				}
+			}
 		}
-		result <- ele
 	}()
 
 	return result
 }
 
-func Broadcast[Elem any](ch <-chan Elem, cnt int) []<-chan Elem {
+func Broadcast[Elem any](ch <-chan Elem, cnt int, opts ...ChanOpt) []<-chan Elem {
 	if cnt <= 0 {
 		return []<-chan Elem{}
 	}
 
+	args := newChanArgs(opts)
 	rwResults := make([]chan Elem, cnt)
 	roResults := make([]<-chan Elem, cnt)
 	for idx := 0; idx < cnt; idx++ {
-		result := make(chan Elem)
+		result := make(chan Elem, args.capacity)
 		rwResults[idx] = result
 		roResults[idx] = result
 	}
@@ -138,9 +529,15 @@ func Broadcast[Elem any](ch <-chan Elem, cnt int) []<-chan Elem {
 			defer close(result)
 		}
 
-		for ele := range ch {
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
 			for _, result := range rwResults {
-				result <- ele
+				if !send(args.ctx, result, ele) {
+					return
+				}
 			}
 		}
 	}()
@@ -148,41 +545,284 @@ func Broadcast[Elem any](ch <-chan Elem, cnt int) []<-chan Elem {
 	return roResults
 }
 
-func Buffer[Elem any](ch <-chan Elem, size int) <-chan []Elem {
+func Buffer[Elem any](ch <-chan Elem, size int, opts ...ChanOpt) <-chan []Elem {
 	if size <= 0 {
 		return Map(ch, func(ele Elem) []Elem {
 			return []Elem{}
-		})
+		}, opts...)
 	}
 
-	result := make(chan []Elem)
+	args := newChanArgs(opts)
+	result := make(chan []Elem, args.capacity)
 	go func() {
 		defer close(result)
 
 		buffer := []Elem{}
-		for ele := range ch {
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				break
+			}
 			buffer = append(buffer, ele)
 			if len(buffer) >= size {
-				result <- buffer
+				if !send(args.ctx, result, buffer) {
+					return
+				}
 				buffer = []Elem{}
 			}
 		}
 
-		if len(buffer) > 0 {
-			result <- buffer
+		if len(buffer) > 0 && args.ctx.Err() == nil {
+			send(args.ctx, result, buffer)
+		}
+	}()
+
+	return result
+}
+
+// BufferTimeout behaves like Buffer, but additionally emits
+// a partial buffer once maxWait has elapsed since its first
+// element arrived, so that slow streams are not held up
+// waiting for the buffer to fill.
+func BufferTimeout[Elem any](ch <-chan Elem, size int, maxWait time.Duration, opts ...ChanOpt) <-chan []Elem {
+	if size <= 0 {
+		return Buffer(ch, size, opts...)
+	}
+
+	args := newChanArgs(opts)
+	result := make(chan []Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		buffer := []Elem{}
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer = nil
+				fire = nil
+			}
+			out := buffer
+			buffer = []Elem{}
+			return send(args.ctx, result, out)
+		}
+
+		for {
+			select {
+			case ele, ok := <-ch:
+				if !ok {
+					if len(buffer) > 0 {
+						flush()
+					}
+					return
+				}
+
+				buffer = append(buffer, ele)
+				if len(buffer) >= size {
+					if !flush() {
+						return
+					}
+				} else if timer == nil {
+					timer = time.NewTimer(maxWait)
+					fire = timer.C
+				}
+			case <-fire:
+				if !flush() {
+					return
+				}
+			case <-args.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// BufferWithOverflow receives elements from ch into a queue holding
+// at most capacity elements, forwarding them as the consumer is ready.
+// This lets a producer run ahead of a slow consumer without the
+// unbounded memory growth of collecting elements into slices.
+// When the queue is full, strategy determines whether to wait for
+// the consumer, drop the arriving element, or drop the oldest
+// queued element. A capacity less than one is treated as one.
+// Any queued elements are still emitted once ch closes.
+func BufferWithOverflow[Elem any](ch <-chan Elem, capacity int, strategy OverflowStrategy, opts ...ChanOpt) <-chan Elem {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		queue := make([]Elem, 0, capacity)
+		in := ch
+		for in != nil || len(queue) > 0 {
+			var out chan<- Elem
+			var next Elem
+			if len(queue) > 0 {
+				out = result
+				next = queue[0]
+			}
+
+			incoming := in
+			if len(queue) >= capacity && strategy == OverflowBlock {
+				incoming = nil
+			}
+
+			select {
+			case <-args.ctx.Done():
+				return
+			case out <- next:
+				queue = queue[1:]
+			case ele, ok := <-incoming:
+				if !ok {
+					in = nil
+					continue
+				}
+
+				switch {
+				case len(queue) < capacity:
+					queue = append(queue, ele)
+				case strategy == OverflowDropOldest:
+					queue = append(queue[1:], ele)
+				}
+			}
+		}
+	}()
+
+	return result
+}
+
+// ChunkByKey groups consecutive elements received from ch that share
+// the same key, emitting each group as a slice once an element with
+// a different key arrives or ch closes. Elements with the same key
+// are only grouped together if they are adjacent, so ch should
+// already be ordered by key, such as events sorted by session.
+func ChunkByKey[Elem any, K comparable](ch <-chan Elem, key func(Elem) K, opts ...ChanOpt) <-chan []Elem {
+	args := newChanArgs(opts)
+	result := make(chan []Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		var chunk []Elem
+		var current K
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				break
+			}
+
+			k := key(ele)
+			if len(chunk) > 0 && k != current {
+				if !send(args.ctx, result, chunk) {
+					return
+				}
+				chunk = nil
+			}
+			current = k
+			chunk = append(chunk, ele)
+		}
+
+		if len(chunk) > 0 && args.ctx.Err() == nil {
+			send(args.ctx, result, chunk)
 		}
 	}()
 
 	return result
 }
 
+// Collect receives every element from ch into a slice, returning
+// once ch closes. If ctx is cancelled first, it returns the elements
+// received so far along with ctx.Err().
+func Collect[Elem any](ctx context.Context, ch <-chan Elem) ([]Elem, error) {
+	result := make([]Elem, 0)
+	for {
+		ele, ok := recv(ctx, ch)
+		if !ok {
+			return result, ctx.Err()
+		}
+		result = append(result, ele)
+	}
+}
+
+// CollectMap receives every key value pair from ch into a map,
+// returning once ch closes. If the same key is received twice,
+// the last value wins. If ctx is cancelled first, it returns the
+// pairs received so far along with ctx.Err().
+func CollectMap[K comparable, V any](ctx context.Context, ch <-chan pairs.Pair[K, V]) (map[K]V, error) {
+	result := make(map[K]V)
+	for {
+		kv, ok := recv(ctx, ch)
+		if !ok {
+			return result, ctx.Err()
+		}
+		result[kv.Left] = kv.Right
+	}
+}
+
+// CollectSet receives every distinct element from ch into a set,
+// returning once ch closes. If ctx is cancelled first, it returns
+// the elements received so far along with ctx.Err().
+func CollectSet[Elem comparable](ctx context.Context, ch <-chan Elem) (map[Elem]struct{}, error) {
+	result := make(map[Elem]struct{})
+	for {
+		ele, ok := recv(ctx, ch)
+		if !ok {
+			return result, ctx.Err()
+		}
+		result[ele] = struct{}{}
+	}
+}
+
+// CollectUntilErr receives the values from a channel of results,
+// where each result pairs a value with an error, into a slice.
+// It stops at the first result with a non-nil error, returning
+// the values received before it along with that error.
+func CollectUntilErr[Elem any](ch <-chan pairs.Pair[Elem, error]) ([]Elem, error) {
+	result := make([]Elem, 0)
+	for res := range ch {
+		if res.Right != nil {
+			return result, res.Right
+		}
+		result = append(result, res.Left)
+	}
+
+	return result, nil
+}
+
 func Concat[Elem any](chs ...<-chan Elem) <-chan Elem {
-	result := make(chan Elem)
+	return ConcatSlice(chs)
+}
+
+// ConcatSlice behaves like Concat,
+// but accepts its inputs as a slice alongside ChanOpts.
+func ConcatSlice[Elem any](chs []<-chan Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
 		for _, ch := range chs {
-			for ele := range ch {
-				result <- ele
+			for {
+				ele, ok := recv(args.ctx, ch)
+				if !ok {
+					break
+				}
+				if !send(args.ctx, result, ele) {
+					return
+				}
+			}
+			if args.ctx.Err() != nil {
+				return
 			}
 		}
 	}()
@@ -259,22 +899,43 @@ func Count[Elem any](ch <-chan Elem, fn func(Elem) bool) int {
 	return n
 }
 
-func Distinct[Elem comparable](ch <-chan Elem) <-chan Elem {
+// Debounce emits an element from ch only once d has elapsed
+// without ch producing another element, dropping any elements
+// superseded during that time. If ch closes while an element
+// is pending, that element is emitted immediately.
+func Debounce[Elem any](ch <-chan Elem, d time.Duration, opts ...ChanOpt) <-chan Elem {
+	return debounce(ch, d, false, opts)
+}
+
+// DebounceLeading emits an element from ch immediately
+// if ch has been silent for at least d, dropping any elements
+// that follow it until another d of silence has elapsed.
+func DebounceLeading[Elem any](ch <-chan Elem, d time.Duration, opts ...ChanOpt) <-chan Elem {
+	return debounce(ch, d, true, opts)
+}
+
+func Distinct[Elem comparable](ch <-chan Elem, opts ...DistinctOpt) <-chan Elem {
 	return DistinctBy(ch, func(ele Elem) Elem {
 		return ele
-	})
+	}, opts...)
 }
 
-func DistinctBy[Elem any, Comp comparable](ch <-chan Elem, fn func(Elem) Comp) <-chan Elem {
-	result := make(chan Elem)
+func DistinctBy[Elem any, Comp comparable](ch <-chan Elem, fn func(Elem) Comp, opts ...DistinctOpt) <-chan Elem {
+	args := newDistinctArgs(opts)
+	result := make(chan Elem, args.capacity)
 
 	go func() {
-		seen := map[Comp]struct{}{}
-		for ele := range ch {
-			comp := fn(ele)
-			if _, ok := seen[comp]; !ok {
-				result <- ele
-				seen[comp] = struct{}{}
+		defer close(result)
+		seen := newSeenCache[Comp](args.maxSize, args.ttl)
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			if !seen.observe(fn(ele)) {
+				if !send(args.ctx, result, ele) {
+					return
+				}
 			}
 		}
 	}()
@@ -282,15 +943,25 @@ func DistinctBy[Elem any, Comp comparable](ch <-chan Elem, fn func(Elem) Comp) <
 	return result
 }
 
-func Distribute[Elem any](ch <-chan Elem, cnt int) []<-chan Elem {
+// Distribute splits the elements received from ch across cnt channels.
+// By default, each element goes to whichever output's consumer is ready
+// first, which can skew work badly when elements vary in cost; use
+// DistributeLeastLoaded to balance queued work instead, and
+// DistributeOnDepth to observe how much work is queued for each output.
+func Distribute[Elem any](ch <-chan Elem, cnt int, opts ...DistributeOpt) []<-chan Elem {
 	if cnt <= 0 {
 		return []<-chan Elem{}
 	}
 
+	args := newDistributeArgs(opts)
+	if args.leastLoaded {
+		return distributeLeastLoaded(ch, cnt, args)
+	}
+
 	rwResults := make([]chan Elem, cnt)
 	roResults := make([]<-chan Elem, cnt)
 	for idx := 0; idx < cnt; idx++ {
-		result := make(chan Elem)
+		result := make(chan Elem, args.capacity)
 		rwResults[idx] = result
 		roResults[idx] = result
 	}
@@ -298,8 +969,14 @@ func Distribute[Elem any](ch <-chan Elem, cnt int) []<-chan Elem {
 	for _, rwResult := range rwResults {
 		go func(rwResult chan Elem) {
 			defer close(rwResult)
-			for ele := range ch {
-				rwResult <- ele
+			for {
+				ele, ok := recv(args.ctx, ch)
+				if !ok {
+					return
+				}
+				if !send(args.ctx, rwResult, ele) {
+					return
+				}
 			}
 		}(rwResult)
 	}
@@ -307,34 +984,60 @@ func Distribute[Elem any](ch <-chan Elem, cnt int) []<-chan Elem {
 	return roResults
 }
 
-func Drop[Elem any](ch <-chan Elem, num int) <-chan Elem {
-	result := make(chan Elem)
+// Drain receives and discards every element from ch
+// until it closes, returning the number of elements discarded.
+// It is useful for unblocking the producer of an abandoned channel.
+func Drain[Elem any](ch <-chan Elem) int {
+	cnt := 0
+	for range ch {
+		cnt++
+	}
+
+	return cnt
+}
+
+func Drop[Elem any](ch <-chan Elem, num int, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
-		for ele := range ch {
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
 			if num > 0 {
 				num--
 				continue
 			}
 
-			result <- ele
+			if !send(args.ctx, result, ele) {
+				return
+			}
 		}
 	}()
 
 	return result
 }
 
-func DropWhile[Elem any](ch <-chan Elem, fn func(Elem) bool) <-chan Elem {
-	result := make(chan Elem)
+func DropWhile[Elem any](ch <-chan Elem, fn func(Elem) bool, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
 		done := false
-		for ele := range ch {
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
 			if !done && fn(ele) {
 				done = true
 			}
 			if done {
-				result <- ele
+				if !send(args.ctx, result, ele) {
+					return
+				}
 			}
 		}
 	}()
@@ -377,19 +1080,45 @@ func EndsWithSequence[Elem comparable](ch <-chan Elem, subseq []Elem) bool {
 	return true
 }
 
+// Enumerate pairs each element received from ch with its
+// zero-based position in the stream.
+func Enumerate[Elem any](ch <-chan Elem, opts ...ChanOpt) <-chan pairs.Pair[int, Elem] {
+	idx := -1
+	return Map(ch, func(ele Elem) pairs.Pair[int, Elem] {
+		idx++
+		return pairs.New(idx, ele)
+	}, opts...)
+}
+
 func Equals[Elem comparable](a, b <-chan Elem) bool {
 	return Corresponds(a, b, func(i, j Elem) bool {
 		return i == j
 	})
 }
 
-func Filter[Elem any](ch <-chan Elem, fn func(Elem) bool) <-chan Elem {
-	result := make(chan Elem)
+// ExhaustMap emits the elements of the channel returned by fn for an
+// element of ch, ignoring any elements received from ch while that
+// inner channel remains open. It suits flows where a request already
+// in progress should not be interrupted, such as a submit button
+// pressed repeatedly.
+func ExhaustMap[From, To any](ch <-chan From, fn func(From) <-chan To, opts ...ChanOpt) <-chan To {
+	return switchMap(ch, fn, true, opts)
+}
+
+func Filter[Elem any](ch <-chan Elem, fn func(Elem) bool, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
-		for ele := range ch {
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
 			if fn(ele) {
-				result <- ele
+				if !send(args.ctx, result, ele) {
+					return
+				}
 			}
 		}
 	}()
@@ -397,25 +1126,73 @@ func Filter[Elem any](ch <-chan Elem, fn func(Elem) bool) <-chan Elem {
 	return result
 }
 
-func First[Elem any](ch <-chan Elem) Elem {
-	return <-ch
+// FilterConcurrent behaves like Filter, but evaluates fn against
+// up to workers elements at once. By default, passing elements
+// are emitted as soon as they are ready; pass PreserveOrder
+// to emit them in input order instead.
+func FilterConcurrent[Elem any](ch <-chan Elem, fn func(Elem) bool, workers int, opts ...ChanOpt) <-chan Elem {
+	return concurrently(ch, func(ele Elem) (Elem, bool) {
+		return ele, fn(ele)
+	}, workers, newChanArgs(opts))
+}
+
+// FilterOk applies the predicate fn to the value of each successful
+// result received from ch, dropping those that fail it.
+// Results with a non-nil error are forwarded untouched.
+func FilterOk[Elem any](ch <-chan pairs.Pair[Elem, error], fn func(Elem) bool, opts ...ChanOpt) <-chan pairs.Pair[Elem, error] {
+	return Filter(ch, func(res pairs.Pair[Elem, error]) bool {
+		return res.Right != nil || fn(res.Left)
+	}, opts...)
+}
+
+// First returns the first element received from ch.
+// It returns false if ch closes without producing an element,
+// or if the context or timeout given by opts expires first.
+func First[Elem any](ch <-chan Elem, opts ...LookupOpt) (Elem, bool) {
+	return FirstWhere(ch, func(Elem) bool { return true }, opts...)
 }
 
-func FirstWhere[Elem any](ch <-chan Elem, fn func(Elem) bool) <-chan Elem {
-	return NthWhere(ch, 1, fn)
+// FirstWhere returns the first element received from ch that passes fn,
+// discarding any that precede it. It returns false if ch closes without
+// producing such an element, or if the context or timeout given by opts
+// expires first.
+func FirstWhere[Elem any](ch <-chan Elem, fn func(Elem) bool, opts ...LookupOpt) (Elem, bool) {
+	ctx, cancel := lookupContext(newLookupArgs(opts))
+	defer cancel()
+
+	for {
+		ele, ok := recv(ctx, ch)
+		if !ok {
+			return ele, false
+		}
+		if fn(ele) {
+			return ele, true
+		}
+	}
 }
 
-func FlatMap[From, To any](ch <-chan From, fn func(From) <-chan To) <-chan To {
-	return Flatten(Map(ch, fn))
+func FlatMap[From, To any](ch <-chan From, fn func(From) <-chan To, opts ...ChanOpt) <-chan To {
+	return Flatten(Map(ch, fn, opts...), opts...)
 }
 
-func Flatten[Elem any](ch <-chan <-chan Elem) <-chan Elem {
-	result := make(chan Elem)
+func Flatten[Elem any](ch <-chan <-chan Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
-		for subch := range ch {
-			for ele := range subch {
-				result <- ele
+		for {
+			subch, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			for {
+				ele, ok := recv(args.ctx, subch)
+				if !ok {
+					break
+				}
+				if !send(args.ctx, result, ele) {
+					return
+				}
 			}
 		}
 	}()
@@ -423,70 +1200,355 @@ func Flatten[Elem any](ch <-chan <-chan Elem) <-chan Elem {
 	return result
 }
 
-func Last[Elem any](ch <-chan Elem, fn func(Elem) bool) <-chan Elem {
-	result := make(chan Elem)
-	go func() {
-		defer close(result)
-
-		var found bool
-		var target Elem
-		for ele := range ch {
+// ForEach performs fn on each element received from ch,
+// returning once ch closes.
+func ForEach[Elem any](ch <-chan Elem, fn func(Elem)) {
+	for ele := range ch {
+		fn(ele)
+	}
+}
+
+// ForEachConcurrent behaves like ForEach, but performs fn on
+// up to workers elements at once. It returns once ch closes
+// and every call to fn has completed, or once the context
+// provided through WithContext is cancelled.
+func ForEachConcurrent[Elem any](ch <-chan Elem, fn func(Elem), workers int, opts ...ChanOpt) {
+	done := concurrently(ch, func(ele Elem) (struct{}, bool) {
+		fn(ele)
+		return struct{}{}, false
+	}, workers, newChanArgs(opts))
+
+	for range done {
+	}
+}
+
+// GroupBy routes each element received from ch to a channel
+// dedicated to the result of calling key on it. The first time
+// a key is seen, its channel is emitted alongside the key.
+// Each group channel receives its elements in order, and every
+// channel closes once ch closes. Because elements are routed one
+// at a time, a group that is not read blocks all other groups,
+// so groups should be consumed concurrently.
+func GroupBy[Elem any, K comparable](ch <-chan Elem, key func(Elem) K, opts ...ChanOpt) <-chan pairs.Pair[K, <-chan Elem] {
+	args := newChanArgs(opts)
+	result := make(chan pairs.Pair[K, <-chan Elem], args.capacity)
+	go func() {
+		groups := make(map[K]chan Elem)
+		defer func() {
+			for _, group := range groups {
+				close(group)
+			}
+			close(result)
+		}()
+
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+
+			k := key(ele)
+			group, ok := groups[k]
+			if !ok {
+				group = make(chan Elem, args.capacity)
+				groups[k] = group
+				if !send(args.ctx, result, pairs.New(k, (<-chan Elem)(group))) {
+					return
+				}
+			}
+
+			if !send(args.ctx, group, ele) {
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// Latest forwards elements from ch, but if the consumer falls
+// behind, only the most recently received element is held for it
+// and any older unconsumed elements are dropped.
+func Latest[Elem any](ch <-chan Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		var latest Elem
+		pending := false
+		for {
+			// Only offer an element to the consumer
+			// while there is one pending.
+			var out chan<- Elem
+			if pending {
+				out = result
+			}
+
+			select {
+			case ele, ok := <-ch:
+				if !ok {
+					if pending {
+						send(args.ctx, result, latest)
+					}
+					return
+				}
+				latest = ele
+				pending = true
+			case out <- latest:
+				pending = false
+			case <-args.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// Last returns the final element received from ch once it closes.
+// It returns false if ch produces no elements, or if the context
+// or timeout given by opts expires before ch closes.
+func Last[Elem any](ch <-chan Elem, opts ...LookupOpt) (Elem, bool) {
+	return LastWhere(ch, func(Elem) bool { return true }, opts...)
+}
+
+// LastWhere returns the final element received from ch that passes fn
+// once ch closes. It returns false if no element passes fn, or if the
+// context or timeout given by opts expires before ch closes.
+func LastWhere[Elem any](ch <-chan Elem, fn func(Elem) bool, opts ...LookupOpt) (Elem, bool) {
+	ctx, cancel := lookupContext(newLookupArgs(opts))
+	defer cancel()
+
+	var found bool
+	var target Elem
+	for {
+		ele, ok := recv(ctx, ch)
+		if !ok {
+			break
+		}
+		if fn(ele) {
 			found = true
 			target = ele
 		}
+	}
+
+	if !found || ctx.Err() != nil {
+		var zero Elem
+		return zero, false
+	}
+
+	return target, true
+}
 
-		if found {
-			result <- target
+func Map[From, To any](ch <-chan From, fn func(From) To, opts ...ChanOpt) <-chan To {
+	args := newChanArgs(opts)
+	result := make(chan To, args.capacity)
+	go func() {
+		defer close(result)
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			if !send(args.ctx, result, fn(ele)) {
+				return
+			}
 		}
 	}()
 
 	return result
 }
 
-func LastWhere[Elem any](ch <-chan Elem, fn func(Elem) bool) <-chan Elem {
-	result := make(chan Elem)
+// MapConcurrent behaves like Map, but applies fn to up to
+// workers elements at once. By default, results are emitted
+// as soon as they are ready; pass PreserveOrder to emit them
+// in input order instead.
+func MapConcurrent[From, To any](ch <-chan From, fn func(From) To, workers int, opts ...ChanOpt) <-chan To {
+	return concurrently(ch, func(ele From) (To, bool) {
+		return fn(ele), true
+	}, workers, newChanArgs(opts))
+}
+
+// MapOk applies fn to the value of each successful result
+// received from ch. Results with a non-nil error are
+// forwarded with their error and a zero value.
+func MapOk[From, To any](ch <-chan pairs.Pair[From, error], fn func(From) To, opts ...ChanOpt) <-chan pairs.Pair[To, error] {
+	return Map(ch, func(res pairs.Pair[From, error]) pairs.Pair[To, error] {
+		if res.Right != nil {
+			var zero To
+			return pairs.New(zero, res.Right)
+		}
+		return pairs.New(fn(res.Left), error(nil))
+	}, opts...)
+}
+
+// Max returns the largest element received from ch once it closes.
+// It returns an error if ch produces no elements.
+func Max[Elem constraints.Ordered](ch <-chan Elem) (Elem, error) {
+	return MaxBy(ch, func(a, b Elem) bool { return a < b })
+}
+
+// MaxBy returns the largest element received from ch once it closes,
+// as determined by less. Ties are resolved in favor of the earliest element.
+// It returns an error if ch produces no elements.
+func MaxBy[Elem any](ch <-chan Elem, less func(a, b Elem) bool) (Elem, error) {
+	return MinBy(ch, func(a, b Elem) bool { return less(b, a) })
+}
+
+func Merge[Elem any](chs ...<-chan Elem) <-chan Elem {
+	return MergeSlice(chs)
+}
+
+// MergePriority combines high and low into a single channel,
+// always taking an element from high when one is available
+// and only falling back to low when high has nothing ready.
+func MergePriority[Elem any](high, low <-chan Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
 
-		var found bool
-		var target Elem
-		for ele := range ch {
-			if fn(ele) {
-				found = true
-				target = ele
+		for high != nil || low != nil {
+			var ele Elem
+			var ok bool
+
+			// Check high alone first, since select
+			// chooses randomly among ready cases.
+			select {
+			case ele, ok = <-high:
+				if !ok {
+					high = nil
+					continue
+				}
+			default:
+				select {
+				case ele, ok = <-high:
+					if !ok {
+						high = nil
+						continue
+					}
+				case ele, ok = <-low:
+					if !ok {
+						low = nil
+						continue
+					}
+				case <-args.ctx.Done():
+					return
+				}
+			}
+
+			if !send(args.ctx, result, ele) {
+				return
 			}
 		}
+	}()
+
+	return result
+}
 
-		if found {
-			result <- target
+// MergeRoundRobin combines chs into a single channel,
+// taking one element from each open channel in turn,
+// so that the interleaving of the inputs is deterministic.
+func MergeRoundRobin[Elem any](chs ...<-chan Elem) <-chan Elem {
+	return MergeRoundRobinSlice(chs)
+}
+
+// MergeRoundRobinSlice behaves like MergeRoundRobin,
+// but accepts its inputs as a slice alongside ChanOpts.
+func MergeRoundRobinSlice[Elem any](chs []<-chan Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		open := make([]<-chan Elem, len(chs))
+		copy(open, chs)
+		for len(open) > 0 {
+			next := open[:0]
+			for _, ch := range open {
+				ele, ok := recv(args.ctx, ch)
+				if !ok {
+					if args.ctx.Err() != nil {
+						return
+					}
+					continue
+				}
+				if !send(args.ctx, result, ele) {
+					return
+				}
+				next = append(next, ch)
+			}
+			open = next
 		}
 	}()
 
 	return result
 }
 
-func Map[From, To any](ch <-chan From, fn func(From) To) <-chan To {
-	result := make(chan To)
+// MergeSorted combines chs, each of which must already be
+// sorted according to less, into a single sorted channel.
+// It holds at most one pending element per input.
+func MergeSorted[Elem any](less func(a, b Elem) bool, chs ...<-chan Elem) <-chan Elem {
+	return MergeSortedSlice(less, chs)
+}
+
+// MergeSortedSlice behaves like MergeSorted,
+// but accepts its inputs as a slice alongside ChanOpts.
+func MergeSortedSlice[Elem any](less func(a, b Elem) bool, chs []<-chan Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
-		for ele := range ch {
-			result <- fn(ele)
+
+		h := &mergeHeap[Elem]{less: less}
+		for _, ch := range chs {
+			if ele, ok := recv(args.ctx, ch); ok {
+				h.items = append(h.items, mergeItem[Elem]{ele: ele, ch: ch})
+			} else if args.ctx.Err() != nil {
+				return
+			}
+		}
+		heap.Init(h)
+
+		for h.Len() > 0 {
+			head := h.items[0]
+			if !send(args.ctx, result, head.ele) {
+				return
+			}
+			if ele, ok := recv(args.ctx, head.ch); ok {
+				h.items[0].ele = ele
+				heap.Fix(h, 0)
+			} else if args.ctx.Err() != nil {
+				return
+			} else {
+				heap.Pop(h)
+			}
 		}
 	}()
 
 	return result
 }
 
-func Merge[Elem any](chs ...<-chan Elem) <-chan Elem {
-	result := make(chan Elem)
+// MergeSlice behaves like Merge,
+// but accepts its inputs as a slice alongside ChanOpts.
+func MergeSlice[Elem any](chs []<-chan Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 
 	var wg sync.WaitGroup
 	for _, ch := range chs {
 		wg.Add(1)
 		go func(ch <-chan Elem) {
 			defer wg.Done()
-			for ele := range ch {
-				result <- ele
+			for {
+				ele, ok := recv(args.ctx, ch)
+				if !ok {
+					return
+				}
+				if !send(args.ctx, result, ele) {
+					return
+				}
 			}
 		}(ch)
 	}
@@ -498,21 +1560,86 @@ func Merge[Elem any](chs ...<-chan Elem) <-chan Elem {
 	return result
 }
 
-func NthWhere[Elem any](ch <-chan Elem, n int, fn func(Elem) bool) <-chan Elem {
-	result := make(chan Elem)
+// Min returns the smallest element received from ch once it closes.
+// It returns an error if ch produces no elements.
+func Min[Elem constraints.Ordered](ch <-chan Elem) (Elem, error) {
+	return MinBy(ch, func(a, b Elem) bool { return a < b })
+}
+
+// MinBy returns the smallest element received from ch once it closes,
+// as determined by less. Ties are resolved in favor of the earliest element.
+// It returns an error if ch produces no elements.
+func MinBy[Elem any](ch <-chan Elem, less func(a, b Elem) bool) (Elem, error) {
+	best, ok := <-ch
+	if !ok {
+		return best, errors.New("no such element")
+	}
+
+	for ele := range ch {
+		if less(ele, best) {
+			best = ele
+		}
+	}
+
+	return best, nil
+}
+
+func NthWhere[Elem any](ch <-chan Elem, n int, fn func(Elem) bool, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
 		if n <= 0 {
 			return
 		}
 
-		for ele := range ch {
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
 			if fn(ele) {
 				n--
 				if n == 0 {
-					result <- ele
-					break
+					send(args.ctx, result, ele)
+					return
+				}
+			}
+		}
+	}()
+
+	return result
+}
+
+// OrDone forwards elements from ch until either ch closes
+// or done is closed or receives a value, whichever comes first.
+// It allows a consumer to stop reading from a stage
+// without leaking the goroutine feeding it.
+func OrDone[Elem any](done <-chan struct{}, ch <-chan Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+		for {
+			var ele Elem
+			var ok bool
+			select {
+			case ele, ok = <-ch:
+				if !ok {
+					return
 				}
+			case <-done:
+				return
+			case <-args.ctx.Done():
+				return
+			}
+
+			select {
+			case result <- ele:
+			case <-done:
+				return
+			case <-args.ctx.Done():
+				return
 			}
 		}
 	}()
@@ -520,17 +1647,24 @@ func NthWhere[Elem any](ch <-chan Elem, n int, fn func(Elem) bool) <-chan Elem {
 	return result
 }
 
-func Partition[Elem any](ch <-chan Elem, fn func(Elem) bool) (<-chan Elem, <-chan Elem) {
-	left := make(chan Elem)
-	right := make(chan Elem)
+func Partition[Elem any](ch <-chan Elem, fn func(Elem) bool, opts ...ChanOpt) (<-chan Elem, <-chan Elem) {
+	args := newChanArgs(opts)
+	left := make(chan Elem, args.capacity)
+	right := make(chan Elem, args.capacity)
 	go func() {
 		defer close(left)
 		defer close(right)
-		for ele := range ch {
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			target := right
 			if fn(ele) {
-				left <- ele
-			} else {
-				right <- ele
+				target = left
+			}
+			if !send(args.ctx, target, ele) {
+				return
 			}
 		}
 	}()
@@ -538,127 +1672,1453 @@ func Partition[Elem any](ch <-chan Elem, fn func(Elem) bool) (<-chan Elem, <-cha
 	return left, right
 }
 
-func Prepend[Elem any](ch <-chan Elem, ele Elem) <-chan Elem {
-	result := make(chan Elem)
+// PartitionByHash splits the elements received from ch across n
+// channels, sending each element to the channel at index hash(ele) % n,
+// so that elements with the same hash always share a channel and
+// retain their relative order. A channel that is not read blocks
+// all others, so they should be consumed concurrently.
+func PartitionByHash[Elem any](ch <-chan Elem, n int, hash func(Elem) uint64, opts ...ChanOpt) []<-chan Elem {
+	return shard(ch, n, func(ele Elem) int {
+		return int(hash(ele) % uint64(n))
+	}, newChanArgs(opts))
+}
+
+// PartitionErrors splits a channel of results into a channel of the
+// values of successful results and a channel for the first error.
+// Once a result with a non-nil error is received, the error is sent
+// on the second channel and both channels are closed. The error channel
+// is buffered, so the first channel can safely be read until it closes
+// before checking the second for an error.
+func PartitionErrors[Elem any](ch <-chan pairs.Pair[Elem, error], opts ...ChanOpt) (<-chan Elem, <-chan error) {
+	return tryTransform(ch, func(res pairs.Pair[Elem, error]) (Elem, bool, error) {
+		return res.Left, true, res.Right
+	}, newChanArgs(opts))
+}
+
+// PartitionN splits the elements received from ch across n channels
+// in round-robin order. Unlike Distribute, the channel each element
+// is sent to depends only on its position in ch. A channel that
+// is not read blocks all others, so they should be consumed concurrently.
+func PartitionN[Elem any](ch <-chan Elem, n int, opts ...ChanOpt) []<-chan Elem {
+	idx := -1
+	return shard(ch, n, func(Elem) int {
+		idx = (idx + 1) % n
+		return idx
+	}, newChanArgs(opts))
+}
+
+func Prepend[Elem any](ch <-chan Elem, ele Elem, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
 
-		result <- ele
-		for e := range ch {
-			result <- e
+		if !send(args.ctx, result, ele) {
+			return
+		}
+		for {
+			e, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			if !send(args.ctx, result, e) {
+				return
+			}
 		}
 	}()
 
 	return result
 }
 
-func Reduce[Elem any, Acc any](ch <-chan Elem, initial Acc, fn func(Acc, Elem) Acc) <-chan Acc {
-	result := make(chan Acc)
+// Product returns the product of the elements received from ch
+// once it closes, or 1 if ch produces no elements.
+func Product[Elem constraints.Numeric](ch <-chan Elem) Elem {
+	return ProductBy(ch, func(ele Elem) Elem { return ele })
+}
+
+// ProductBy returns the product of fn applied to each
+// element received from ch once it closes,
+// or 1 if ch produces no elements.
+func ProductBy[Elem any, Num constraints.Numeric](ch <-chan Elem, fn func(Elem) Num) Num {
+	var product Num = 1
+	for ele := range ch {
+		product *= fn(ele)
+	}
+
+	return product
+}
+
+// RecvTimeout receives the next element from ch, waiting at most d.
+// It returns false if ch is closed or d elapses first.
+func RecvTimeout[Elem any](ch <-chan Elem, d time.Duration) (Elem, bool) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case ele, ok := <-ch:
+		return ele, ok
+	case <-timer.C:
+		var ele Elem
+		return ele, false
+	}
+}
+
+func Reduce[Elem any, Acc any](ch <-chan Elem, initial Acc, fn func(Acc, Elem) Acc, opts ...ChanOpt) <-chan Acc {
+	args := newChanArgs(opts)
+	result := make(chan Acc, args.capacity)
 	go func() {
 		defer close(result)
 		acc := &initial
-		for ele := range ch {
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
 			*acc = fn(*acc, ele)
-			result <- *acc
+			if !send(args.ctx, result, *acc) {
+				return
+			}
 		}
 	}()
 
 	return result
 }
 
-func Size[Elem any](ch <-chan Elem, fn func(Elem) bool) int {
-	return Count(ch, func(ele Elem) bool {
-		return true
-	})
-}
+// Replay consumes ch in the background, recording the last n elements
+// it produces, or every element if n is zero or negative. It returns
+// a function that subscribes to the stream: each subscription first
+// receives the recorded history and then any subsequent elements,
+// closing once ch closes and the subscriber has caught up.
+// A subscriber that falls more than n elements behind skips ahead
+// to the oldest element still recorded.
+func Replay[Elem any](ch <-chan Elem, n int, opts ...ChanOpt) func() <-chan Elem {
+	args := newChanArgs(opts)
+	log := &replayLog[Elem]{limit: n}
+	log.cond = sync.NewCond(&log.mu)
 
-func SplitAt[Elem any](ch <-chan Elem, n int) (<-chan Elem, <-chan Elem) {
-	if n < 0 {
-		n = 0
+	go func() {
+		defer log.finish()
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			log.record(ele)
+		}
+	}()
+
+	// Subscribers blocked waiting for new elements
+	// must be woken if the context is cancelled.
+	if done := args.ctx.Done(); done != nil {
+		go func() {
+			<-done
+			log.finish()
+		}()
 	}
 
-	return Partition(ch, func(ele Elem) bool {
-		n--
-		return n < 0
-	})
+	return func() <-chan Elem {
+		result := make(chan Elem, args.capacity)
+		next := log.start()
+		go func() {
+			defer close(result)
+
+			for {
+				ele, idx, ok := log.wait(next)
+				if !ok || !send(args.ctx, result, ele) {
+					return
+				}
+				next = idx + 1
+			}
+		}()
+
+		return result
+	}
 }
 
-func StartsWith[Elem comparable](ch <-chan Elem, ele Elem) bool {
-	return ele == <-ch
+// retryArgs represent optional arguments to Retry.
+type retryArgs struct {
+	// attempts is the number of consecutive failures
+	// tolerated before giving up, or zero for no limit.
+	attempts int
+	// initialBackoff is the delay after the first failure.
+	initialBackoff time.Duration
+	// maxBackoff caps the delay between attempts.
+	maxBackoff time.Duration
+	// onClose indicates whether a subscription closing
+	// should be treated as a failure.
+	onClose bool
 }
 
-func StartsWithSequence[Elem comparable](ch <-chan Elem, subseq []Elem) bool {
-	if len(subseq) <= 0 {
-		return true
+// RetryOpt represent optional arguments to Retry.
+type RetryOpt func(*retryArgs)
+
+// RetryAttempts is a RetryOpt that limits Retry to n
+// consecutive failed attempts. If n is zero or negative,
+// Retry tries indefinitely. The default is 3.
+func RetryAttempts(n int) RetryOpt {
+	return func(args *retryArgs) {
+		args.attempts = n
 	}
+}
 
-	idx := 0
-	for ele := range ch {
-		if subseq[idx] != ele {
-			return false
-		}
-		idx++
-		if idx >= len(subseq) {
-			break
-		}
+// RetryBackoff is a RetryOpt that waits initial after the first
+// failure, doubling the delay after each consecutive failure
+// up to max. The defaults are 100ms and 10s respectively.
+func RetryBackoff(initial, max time.Duration) RetryOpt {
+	return func(args *retryArgs) {
+		args.initialBackoff = initial
+		args.maxBackoff = max
 	}
+}
 
-	return true
+// RetryOnClose is a RetryOpt that makes Retry resubscribe
+// whenever a subscription closes, rather than only when
+// fn returns an error.
+func RetryOnClose(args *retryArgs) {
+	args.onClose = true
+}
+
+// Retry subscribes to the channel returned by fn and forwards its
+// elements, calling fn again with exponential backoff whenever it
+// returns an error. A subscription that produces at least one element
+// resets the count of consecutive failures. The output closes when
+// a subscription closes, or when the attempts are exhausted.
+func Retry[Elem any](fn func() (<-chan Elem, error), opts ...RetryOpt) <-chan Elem {
+	return RetryWith(fn, opts)
 }
 
-func Take[Elem any](ch <-chan Elem, num int) <-chan Elem {
-	result := make(chan Elem)
+// RetryWith behaves like Retry, but accepts its RetryOpts
+// as a slice alongside ChanOpts. Once the ChanOpt context
+// is cancelled, it stops retrying and closes the output.
+func RetryWith[Elem any](fn func() (<-chan Elem, error), retryOpts []RetryOpt, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	retry := retryArgs{
+		attempts:       3,
+		initialBackoff: 100 * time.Millisecond,
+		maxBackoff:     10 * time.Second,
+	}
+	for _, opt := range retryOpts {
+		opt(&retry)
+	}
+
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
-		for ele := range ch {
-			num--
-			if num < 0 {
-				break
+
+		failures := 0
+		backoff := retry.initialBackoff
+		for {
+			ch, err := fn()
+			if err == nil {
+				received := false
+				for {
+					ele, ok := recv(args.ctx, ch)
+					if !ok {
+						break
+					}
+					received = true
+					if !send(args.ctx, result, ele) {
+						return
+					}
+				}
+
+				if !retry.onClose || args.ctx.Err() != nil {
+					return
+				}
+
+				if received {
+					failures = 0
+					backoff = retry.initialBackoff
+				}
+			}
+
+			failures++
+			if retry.attempts > 0 && failures >= retry.attempts {
+				return
+			}
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-args.ctx.Done():
+				timer.Stop()
+				return
 			}
 
-			result <- ele
+			backoff *= 2
+			if backoff > retry.maxBackoff {
+				backoff = retry.maxBackoff
+			}
 		}
 	}()
 
 	return result
 }
 
-func TakeWhile[Elem any](ch <-chan Elem, fn func(Elem) bool) <-chan Elem {
-	result := make(chan Elem)
+// SampleEvery emits every nth element received from ch,
+// starting with the nth. If n is zero or negative,
+// no elements are emitted.
+func SampleEvery[Elem any](ch <-chan Elem, n int, opts ...ChanOpt) <-chan Elem {
+	cnt := 0
+	return Filter(ch, func(Elem) bool {
+		if n <= 0 {
+			return false
+		}
+		cnt++
+		if cnt < n {
+			return false
+		}
+		cnt = 0
+		return true
+	}, opts...)
+}
+
+// SampleRate emits each element received from ch
+// independently with probability p.
+// Use WithRandom to control the source of randomness.
+func SampleRate[Elem any](ch <-chan Elem, p float64, opts ...RandomOpt) <-chan Elem {
+	args := newRandomArgs(opts)
+	return Filter(ch, func(Elem) bool {
+		return args.random() < p
+	}, withChanArgs(args.chanArgs))
+}
+
+func Size[Elem any](ch <-chan Elem, fn func(Elem) bool) int {
+	return Count(ch, func(ele Elem) bool {
+		return true
+	})
+}
+
+// SkipFor discards the elements received from ch until d has elapsed
+// since SkipFor was called, then forwards the rest.
+func SkipFor[Elem any](ch <-chan Elem, d time.Duration, opts ...ChanOpt) <-chan Elem {
+	return SkipUntil(ch, Timer(d, opts...), opts...)
+}
+
+// SkipUntil discards the elements received from ch until signal
+// is closed or receives a value, then forwards the rest.
+func SkipUntil[Elem any](ch <-chan Elem, signal <-chan struct{}, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
 	go func() {
 		defer close(result)
-		for ele := range ch {
-			if !fn(ele) {
-				break
+
+		for skipping := true; skipping; {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+			case <-signal:
+				skipping = false
+			case <-args.ctx.Done():
+				return
 			}
+		}
 
-			result <- ele
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			if !send(args.ctx, result, ele) {
+				return
+			}
 		}
 	}()
 
 	return result
 }
 
-func Window[Elem any](ch <-chan Elem, size int) <-chan []Elem {
-	if size <= 0 {
-		return Map(ch, func(ele Elem) []Elem {
-			return []Elem{}
-		})
-	}
-
-	result := make(chan []Elem)
+// SlidingWindowByTime emits, every time every elapses, the elements
+// received from ch during the preceding duration size. Consecutive
+// windows overlap whenever size exceeds every. Empty windows are skipped.
+// When ch closes, a final window is emitted if any elements were
+// received since the last one.
+func SlidingWindowByTime[Elem any](ch <-chan Elem, size, every time.Duration, opts ...ChanOpt) <-chan []Elem {
+	args := newChanArgs(opts)
+	result := make(chan []Elem, args.capacity)
 	go func() {
 		defer close(result)
 
-		window := []Elem{}
-		for ele := range ch {
-			window = append(window, ele)
-			if len(window) > size {
-				window = window[1:]
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		received := []timedElem[Elem]{}
+		pending := false
+		window := func(now time.Time) []Elem {
+			cutoff := now.Add(-size)
+			for len(received) > 0 && received[0].at.Before(cutoff) {
+				received = received[1:]
+			}
+
+			result := make([]Elem, len(received))
+			for idx, te := range received {
+				result[idx] = te.ele
+			}
+			return result
+		}
+
+		for {
+			select {
+			case ele, ok := <-ch:
+				if !ok {
+					if w := window(time.Now()); pending && len(w) > 0 {
+						send(args.ctx, result, w)
+					}
+					return
+				}
+				received = append(received, timedElem[Elem]{ele: ele, at: time.Now()})
+				pending = true
+			case now := <-ticker.C:
+				w := window(now)
+				if len(w) == 0 {
+					continue
+				}
+				if !send(args.ctx, result, w) {
+					return
+				}
+				pending = false
+			case <-args.ctx.Done():
+				return
 			}
-			result <- window
 		}
 	}()
 
 	return result
 }
+
+// Sorted buffers every element received from ch and emits
+// them in ascending order once ch closes. Because nothing is
+// emitted until then, its memory use grows with the length of ch;
+// use TrySorted to bound it for long or unbounded streams.
+func Sorted[Elem constraints.Ordered](ch <-chan Elem, opts ...ChanOpt) <-chan Elem {
+	return SortedBy(ch, func(a, b Elem) bool { return a < b }, opts...)
+}
+
+// SortedBy buffers every element received from ch and emits them
+// in the order determined by less once ch closes, preserving the
+// relative order of equal elements. Because nothing is emitted until
+// then, its memory use grows with the length of ch; use TrySortedBy
+// to bound it for long or unbounded streams.
+func SortedBy[Elem any](ch <-chan Elem, less func(a, b Elem) bool, opts ...ChanOpt) <-chan Elem {
+	result, _ := sortedBy(ch, less, 0, newChanArgs(opts))
+	return result
+}
+
+func SplitAt[Elem any](ch <-chan Elem, n int, opts ...ChanOpt) (<-chan Elem, <-chan Elem) {
+	if n < 0 {
+		n = 0
+	}
+
+	return Partition(ch, func(ele Elem) bool {
+		n--
+		return n < 0
+	}, opts...)
+}
+
+func StartsWith[Elem comparable](ch <-chan Elem, ele Elem) bool {
+	return ele == <-ch
+}
+
+func StartsWithSequence[Elem comparable](ch <-chan Elem, subseq []Elem) bool {
+	if len(subseq) <= 0 {
+		return true
+	}
+
+	idx := 0
+	for ele := range ch {
+		if subseq[idx] != ele {
+			return false
+		}
+		idx++
+		if idx >= len(subseq) {
+			break
+		}
+	}
+
+	return true
+}
+
+// Sum returns the sum of the elements received from ch once it closes.
+func Sum[Elem constraints.Numeric](ch <-chan Elem) Elem {
+	return SumBy(ch, func(ele Elem) Elem { return ele })
+}
+
+// SumBy returns the sum of fn applied to each
+// element received from ch once it closes.
+func SumBy[Elem any, Num constraints.Numeric](ch <-chan Elem, fn func(Elem) Num) Num {
+	var sum Num
+	for ele := range ch {
+		sum += fn(ele)
+	}
+
+	return sum
+}
+
+// SwitchMap emits the elements of the channel returned by fn for the
+// most recent element of ch. When a new element arrives, the previous
+// inner channel is abandoned along with any of its elements not yet
+// emitted, so only the latest request wins. Abandoned inner channels
+// are drained in the background until they close or the context given
+// through WithContext is cancelled, so their producers are not blocked.
+func SwitchMap[From, To any](ch <-chan From, fn func(From) <-chan To, opts ...ChanOpt) <-chan To {
+	return switchMap(ch, fn, false, opts)
+}
+
+func Take[Elem any](ch <-chan Elem, num int, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			num--
+			if num < 0 {
+				return
+			}
+
+			if !send(args.ctx, result, ele) {
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// TakeFor forwards elements from ch until d has elapsed
+// since TakeFor was called, then closes its output.
+func TakeFor[Elem any](ch <-chan Elem, d time.Duration, opts ...ChanOpt) <-chan Elem {
+	return TakeUntil(ch, Timer(d, opts...), opts...)
+}
+
+// TakeUntil forwards elements from ch until signal
+// is closed or receives a value, then closes its output.
+func TakeUntil[Elem any](ch <-chan Elem, signal <-chan struct{}, opts ...ChanOpt) <-chan Elem {
+	return OrDone(signal, ch, opts...)
+}
+
+func TakeWhile[Elem any](ch <-chan Elem, fn func(Elem) bool, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok || !fn(ele) {
+				return
+			}
+
+			if !send(args.ctx, result, ele) {
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// Timeout forwards elements from ch, closing its output
+// if ch goes longer than d without producing an element.
+func Timeout[Elem any](ch <-chan Elem, d time.Duration, opts ...ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		for {
+			select {
+			case ele, ok := <-ch:
+				if !ok {
+					return
+				}
+				if !send(args.ctx, result, ele) {
+					return
+				}
+			case <-timer.C:
+				return
+			case <-args.ctx.Done():
+				return
+			}
+
+			// The timer has not fired, so it must be stopped
+			// and drained before it can safely be reset.
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(d)
+		}
+	}()
+
+	return result
+}
+
+// TryDrain discards the elements that are immediately available
+// on ch without blocking, returning the number of elements discarded.
+func TryDrain[Elem any](ch <-chan Elem) int {
+	cnt := 0
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return cnt
+			}
+			cnt++
+		default:
+			return cnt
+		}
+	}
+}
+
+// TryFilter behaves like Filter, but for a fallible predicate.
+// If fn returns an error, it is sent on the second returned channel
+// and both channels are closed without processing further elements.
+// The error channel is buffered, so the first channel can safely be
+// read until it closes before checking the second for an error.
+// To tear down the stages feeding ch as well,
+// cancel the context provided through WithContext.
+func TryFilter[Elem any](ch <-chan Elem, fn func(Elem) (bool, error), opts ...ChanOpt) (<-chan Elem, <-chan error) {
+	return tryTransform(ch, func(ele Elem) (Elem, bool, error) {
+		keep, err := fn(ele)
+		return ele, keep, err
+	}, newChanArgs(opts))
+}
+
+// TryForEach performs fn on each element received from ch,
+// stopping at and returning the first error encountered.
+// If ch closes without error, it returns nil.
+func TryForEach[Elem any](ch <-chan Elem, fn func(Elem) error) error {
+	for ele := range ch {
+		if err := fn(ele); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TrySorted behaves like Sorted, but buffers at most maxSize elements.
+// If ch produces more than that, an error is sent on the second returned
+// channel and both channels are closed without emitting any elements.
+// The error channel is buffered, so the first channel can safely be
+// read until it closes before checking the second for an error.
+// If maxSize is zero or negative, the buffer is unbounded.
+func TrySorted[Elem constraints.Ordered](ch <-chan Elem, maxSize int, opts ...ChanOpt) (<-chan Elem, <-chan error) {
+	return TrySortedBy(ch, func(a, b Elem) bool { return a < b }, maxSize, opts...)
+}
+
+// TrySortedBy behaves like SortedBy, but buffers at most maxSize elements.
+// If ch produces more than that, an error is sent on the second returned
+// channel and both channels are closed without emitting any elements.
+// The error channel is buffered, so the first channel can safely be
+// read until it closes before checking the second for an error.
+// If maxSize is zero or negative, the buffer is unbounded.
+// To tear down the stages feeding ch as well,
+// cancel the context provided through WithContext.
+func TrySortedBy[Elem any](ch <-chan Elem, less func(a, b Elem) bool, maxSize int, opts ...ChanOpt) (<-chan Elem, <-chan error) {
+	return sortedBy(ch, less, maxSize, newChanArgs(opts))
+}
+
+// TryMap behaves like Map, but for a fallible mapping function.
+// If fn returns an error, it is sent on the second returned channel
+// and both channels are closed without processing further elements.
+// The error channel is buffered, so the first channel can safely be
+// read until it closes before checking the second for an error.
+// To tear down the stages feeding ch as well,
+// cancel the context provided through WithContext.
+func TryMap[From, To any](ch <-chan From, fn func(From) (To, error), opts ...ChanOpt) (<-chan To, <-chan error) {
+	return tryTransform(ch, func(ele From) (To, bool, error) {
+		out, err := fn(ele)
+		return out, true, err
+	}, newChanArgs(opts))
+}
+
+func Window[Elem any](ch <-chan Elem, size int, opts ...ChanOpt) <-chan []Elem {
+	if size <= 0 {
+		return Map(ch, func(ele Elem) []Elem {
+			return []Elem{}
+		}, opts...)
+	}
+
+	args := newChanArgs(opts)
+	result := make(chan []Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		window := []Elem{}
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			window = append(window, ele)
+			if len(window) > size {
+				window = window[1:]
+			}
+			if !send(args.ctx, result, window) {
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// WindowByTime groups the elements received from ch into
+// consecutive, non-overlapping windows of duration d,
+// emitting each window as it ends. Windows in which no
+// elements arrived are skipped. Any elements in the final,
+// partial window are emitted when ch closes.
+func WindowByTime[Elem any](ch <-chan Elem, d time.Duration, opts ...ChanOpt) <-chan []Elem {
+	args := newChanArgs(opts)
+	result := make(chan []Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+
+		window := []Elem{}
+		for {
+			select {
+			case ele, ok := <-ch:
+				if !ok {
+					if len(window) > 0 {
+						send(args.ctx, result, window)
+					}
+					return
+				}
+				window = append(window, ele)
+			case <-ticker.C:
+				if len(window) == 0 {
+					continue
+				}
+				if !send(args.ctx, result, window) {
+					return
+				}
+				window = []Elem{}
+			case <-args.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+/* Helpers */
+
+// tryTransform applies fn to each element of ch, emitting each result
+// for which fn reports true, until fn returns an error. That error is
+// sent on a separate channel with room for it, so that sending it never
+// waits for a consumer that is still reading the results.
+func tryTransform[From, To any](ch <-chan From, fn func(From) (To, bool, error), args chanArgs) (<-chan To, <-chan error) {
+	result := make(chan To, args.capacity)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(result)
+		defer close(errs)
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+
+			out, keep, err := fn(ele)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if keep && !send(args.ctx, result, out) {
+				return
+			}
+		}
+	}()
+
+	return result, errs
+}
+
+// mergeItem is the head element of one input to MergeSorted.
+type mergeItem[T any] struct {
+	ele T
+	ch  <-chan T
+}
+
+// mergeHeap is a min-heap of mergeItems ordered by less.
+type mergeHeap[T any] struct {
+	items []mergeItem[T]
+	less  func(a, b T) bool
+}
+
+func (h *mergeHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	return h.less(h.items[i].ele, h.items[j].ele)
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *mergeHeap[T]) Push(x any) {
+	h.items = append(h.items, x.(mergeItem[T]))
+}
+
+func (h *mergeHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// seenCache tracks which keys have been observed, optionally
+// forgetting keys by recency of use or age.
+type seenCache[K comparable] struct {
+	maxSize int
+	ttl     time.Duration
+	entries map[K]*list.Element
+	// order holds a seenEntry per key,
+	// from least to most recently seen.
+	order *list.List
+}
+
+// seenEntry records when a key in a seenCache was last seen.
+type seenEntry[K comparable] struct {
+	key K
+	at  time.Time
+}
+
+// newSeenCache creates a seenCache retaining at most maxSize keys
+// for at most ttl. Zero values indicate no bound.
+func newSeenCache[K comparable](maxSize int, ttl time.Duration) *seenCache[K] {
+	return &seenCache[K]{
+		maxSize: maxSize,
+		ttl:     ttl,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// observe records that key was just seen,
+// reporting whether it was already being tracked.
+func (c *seenCache[K]) observe(key K) bool {
+	// Without bounds, there is no need to track recency.
+	if c.maxSize <= 0 && c.ttl <= 0 {
+		if _, ok := c.entries[key]; ok {
+			return true
+		}
+		c.entries[key] = nil
+		return false
+	}
+
+	now := time.Now()
+	if c.ttl > 0 {
+		cutoff := now.Add(-c.ttl)
+		for front := c.order.Front(); front != nil; front = c.order.Front() {
+			entry := front.Value.(seenEntry[K])
+			if !entry.at.Before(cutoff) {
+				break
+			}
+			c.order.Remove(front)
+			delete(c.entries, entry.key)
+		}
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = seenEntry[K]{key: key, at: now}
+		c.order.MoveToBack(elem)
+		return true
+	}
+
+	c.entries[key] = c.order.PushBack(seenEntry[K]{key: key, at: now})
+	if c.maxSize > 0 && c.order.Len() > c.maxSize {
+		front := c.order.Front()
+		c.order.Remove(front)
+		delete(c.entries, front.Value.(seenEntry[K]).key)
+	}
+
+	return false
+}
+
+// replayLog holds the elements recorded by Replay.
+type replayLog[T any] struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	// eles holds the recorded elements, the first of which
+	// is the offset-th element ever produced.
+	eles   []T
+	offset int
+	// limit is the number of elements to retain,
+	// or zero or negative to retain all of them.
+	limit int
+	done  bool
+}
+
+// record appends ele to the log, discarding
+// the oldest element if the limit is exceeded.
+func (l *replayLog[T]) record(ele T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.eles = append(l.eles, ele)
+	if l.limit > 0 && len(l.eles) > l.limit {
+		var zero T
+		l.eles[0] = zero
+		l.eles = l.eles[1:]
+		l.offset++
+	}
+	l.cond.Broadcast()
+}
+
+// finish marks that no more elements will be recorded.
+func (l *replayLog[T]) finish() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.done = true
+	l.cond.Broadcast()
+}
+
+// start returns the index of the oldest recorded element.
+func (l *replayLog[T]) start() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.offset
+}
+
+// wait blocks until an element with index at least idx is recorded,
+// returning the earliest such element still retained and its index.
+// It returns false if the log finishes first.
+func (l *replayLog[T]) wait(idx int) (T, int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for idx >= l.offset+len(l.eles) && !l.done {
+		l.cond.Wait()
+	}
+
+	if idx < l.offset {
+		idx = l.offset
+	}
+	if idx >= l.offset+len(l.eles) {
+		var zero T
+		return zero, idx, false
+	}
+
+	return l.eles[idx-l.offset], idx, true
+}
+
+// shard sends each element received from ch to the
+// one of n channels chosen by the index function pick.
+func shard[Elem any](ch <-chan Elem, n int, pick func(Elem) int, args chanArgs) []<-chan Elem {
+	if n <= 0 {
+		return []<-chan Elem{}
+	}
+
+	rwResults := make([]chan Elem, n)
+	roResults := make([]<-chan Elem, n)
+	for idx := 0; idx < n; idx++ {
+		result := make(chan Elem, args.capacity)
+		rwResults[idx] = result
+		roResults[idx] = result
+	}
+
+	go func() {
+		defer func() {
+			for _, result := range rwResults {
+				close(result)
+			}
+		}()
+
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			if !send(args.ctx, rwResults[pick(ele)], ele) {
+				return
+			}
+		}
+	}()
+
+	return roResults
+}
+
+// timedElem records when an element was received.
+type timedElem[T any] struct {
+	ele T
+	at  time.Time
+}
+
+// concurrentResult holds the outcome of processing
+// a single element within concurrently.
+type concurrentResult[T any] struct {
+	ele  T
+	keep bool
+}
+
+// concurrentJob pairs an element with the channel
+// on which its concurrentResult should be delivered.
+type concurrentJob[From, To any] struct {
+	ele From
+	out chan concurrentResult[To]
+}
+
+// concurrently applies fn to the elements of ch using a pool of
+// workers goroutines, emitting each result for which fn reports true.
+// If args.ordered is set, results are emitted in input order.
+func concurrently[From, To any](ch <-chan From, fn func(From) (To, bool), workers int, args chanArgs) <-chan To {
+	if workers < 1 {
+		workers = 1
+	}
+
+	result := make(chan To, args.capacity)
+	if !args.ordered {
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for idx := 0; idx < workers; idx++ {
+			go func() {
+				defer wg.Done()
+				for {
+					ele, ok := recv(args.ctx, ch)
+					if !ok {
+						return
+					}
+					if out, keep := fn(ele); keep {
+						if !send(args.ctx, result, out) {
+							return
+						}
+					}
+				}
+			}()
+		}
+		go func() {
+			defer close(result)
+			wg.Wait()
+		}()
+
+		return result
+	}
+
+	// Each element is handed to a worker alongside a dedicated
+	// result channel, which is also queued in input order.
+	// Bounding the queue by the number of workers keeps
+	// at most that many results outstanding at once.
+	jobs := make(chan concurrentJob[From, To])
+	pending := make(chan chan concurrentResult[To], workers)
+
+	for idx := 0; idx < workers; idx++ {
+		go func() {
+			for job := range jobs {
+				out, keep := fn(job.ele)
+				job.out <- concurrentResult[To]{ele: out, keep: keep}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+			out := make(chan concurrentResult[To], 1)
+			if !send(args.ctx, jobs, concurrentJob[From, To]{ele: ele, out: out}) {
+				return
+			}
+			if !send(args.ctx, pending, out) {
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(result)
+		for out := range pending {
+			res, ok := recv(args.ctx, out)
+			if !ok {
+				return
+			}
+			if res.keep && !send(args.ctx, result, res.ele) {
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// debounce implements Debounce and DebounceLeading,
+// emitting on the leading or trailing edge of each burst.
+func debounce[Elem any](ch <-chan Elem, d time.Duration, leading bool, opts []ChanOpt) <-chan Elem {
+	args := newChanArgs(opts)
+	result := make(chan Elem, args.capacity)
+	go func() {
+		defer close(result)
+
+		// A fresh timer is started for every element, so
+		// fire only ever refers to the most recent one.
+		var timer *time.Timer
+		var fire <-chan time.Time
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		var pending Elem
+		hasPending := false
+		for {
+			select {
+			case ele, ok := <-ch:
+				if !ok {
+					if hasPending {
+						send(args.ctx, result, pending)
+					}
+					return
+				}
+
+				if leading && fire == nil {
+					if !send(args.ctx, result, ele) {
+						return
+					}
+				} else if !leading {
+					pending = ele
+					hasPending = true
+				}
+
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.NewTimer(d)
+				fire = timer.C
+			case <-fire:
+				fire = nil
+				if hasPending {
+					hasPending = false
+					if !send(args.ctx, result, pending) {
+						return
+					}
+				}
+			case <-args.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// sortedBy implements SortedBy and TrySortedBy, buffering at most
+// maxSize elements, or any number if maxSize is zero or negative.
+func sortedBy[Elem any](ch <-chan Elem, less func(a, b Elem) bool, maxSize int, args chanArgs) (<-chan Elem, <-chan error) {
+	result := make(chan Elem, args.capacity)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(result)
+		defer close(errs)
+
+		var buffer []Elem
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				break
+			}
+			if maxSize > 0 && len(buffer) >= maxSize {
+				errs <- errors.New("too many elements to sort")
+				return
+			}
+			buffer = append(buffer, ele)
+		}
+		if args.ctx.Err() != nil {
+			return
+		}
+
+		sort.SliceStable(buffer, func(i, j int) bool {
+			return less(buffer[i], buffer[j])
+		})
+		for _, ele := range buffer {
+			if !send(args.ctx, result, ele) {
+				return
+			}
+		}
+	}()
+
+	return result, errs
+}
+
+// switchMap emits the elements of the inner channels returned by fn.
+// If exhaust is set, elements of ch that arrive while an inner channel
+// is open are ignored; otherwise they replace the open inner channel.
+func switchMap[From, To any](ch <-chan From, fn func(From) <-chan To, exhaust bool, opts []ChanOpt) <-chan To {
+	args := newChanArgs(opts)
+	result := make(chan To, args.capacity)
+	go func() {
+		defer close(result)
+
+		in := ch
+		var inner <-chan To
+		var pending To
+		var hasPending bool
+		for in != nil || inner != nil {
+			var out chan<- To
+			innerRecv := inner
+			if hasPending {
+				out = result
+				innerRecv = nil
+			}
+
+			select {
+			case <-args.ctx.Done():
+				return
+			case out <- pending:
+				hasPending = false
+			case ele, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				if inner != nil {
+					if exhaust {
+						continue
+					}
+					go func(abandoned <-chan To) {
+						for {
+							if _, ok := recv(args.ctx, abandoned); !ok {
+								return
+							}
+						}
+					}(inner)
+				}
+				inner = fn(ele)
+				hasPending = false
+			case ele, ok := <-innerRecv:
+				if !ok {
+					inner = nil
+					continue
+				}
+				pending = ele
+				hasPending = true
+			}
+		}
+	}()
+
+	return result
+}
+
+// distributeLeastLoaded implements Distribute for DistributeLeastLoaded.
+// A dispatcher appends each element to the shortest bounded queue,
+// and a forwarder per output sends queued elements to its consumer.
+// An element being sent counts towards its queue's depth.
+func distributeLeastLoaded[Elem any](ch <-chan Elem, cnt int, args distributeArgs) []<-chan Elem {
+	bound := args.capacity
+	if bound < 1 {
+		bound = 1
+	}
+
+	var mu sync.Mutex
+	cond := sync.NewCond(&mu)
+	queues := make([][]Elem, cnt)
+	depths := make([]int, cnt)
+	done := false
+
+	setDepth := func(idx, depth int) {
+		depths[idx] = depth
+		if args.onDepth != nil {
+			args.onDepth(idx, depth)
+		}
+		cond.Broadcast()
+	}
+
+	roResults := make([]<-chan Elem, cnt)
+	for idx := 0; idx < cnt; idx++ {
+		result := make(chan Elem)
+		roResults[idx] = result
+
+		go func(idx int, result chan Elem) {
+			defer close(result)
+			for {
+				mu.Lock()
+				for len(queues[idx]) == 0 && !done {
+					cond.Wait()
+				}
+				if len(queues[idx]) == 0 {
+					mu.Unlock()
+					return
+				}
+				ele := queues[idx][0]
+				queues[idx] = queues[idx][1:]
+				mu.Unlock()
+
+				ok := send(args.ctx, result, ele)
+
+				mu.Lock()
+				setDepth(idx, depths[idx]-1)
+				mu.Unlock()
+				if !ok {
+					return
+				}
+			}
+		}(idx, result)
+	}
+
+	go func() {
+		defer func() {
+			mu.Lock()
+			done = true
+			cond.Broadcast()
+			mu.Unlock()
+		}()
+
+		for {
+			ele, ok := recv(args.ctx, ch)
+			if !ok {
+				return
+			}
+
+			mu.Lock()
+			for {
+				target := -1
+				for idx, depth := range depths {
+					if depth < bound && (target < 0 || depth < depths[target]) {
+						target = idx
+					}
+				}
+				if target >= 0 {
+					queues[target] = append(queues[target], ele)
+					setDepth(target, depths[target]+1)
+					break
+				}
+				if args.ctx.Err() != nil {
+					mu.Unlock()
+					return
+				}
+				cond.Wait()
+			}
+			mu.Unlock()
+		}
+	}()
+
+	return roResults
+}
+
+// lookupContext derives the context bounding a blocking lookup,
+// applying the timeout from args if one was given.
+func lookupContext(args lookupArgs) (context.Context, context.CancelFunc) {
+	if args.timeout > 0 {
+		return context.WithTimeout(args.ctx, args.timeout)
+	}
+
+	return context.WithCancel(args.ctx)
+}
+
+// newBridgeArgs applies opts over the default bridgeArgs.
+func newBridgeArgs(opts []BridgeOpt) bridgeArgs {
+	args := bridgeArgs{chanArgs: newChanArgs(nil)}
+	for _, opt := range opts {
+		opt.applyBridge(&args)
+	}
+
+	return args
+}
+
+// newDistinctArgs applies opts over the default distinctArgs.
+func newDistinctArgs(opts []DistinctOpt) distinctArgs {
+	args := distinctArgs{chanArgs: newChanArgs(nil)}
+	for _, opt := range opts {
+		opt.applyDistinct(&args)
+	}
+
+	return args
+}
+
+// newDistributeArgs applies opts over the default distributeArgs.
+func newDistributeArgs(opts []DistributeOpt) distributeArgs {
+	args := distributeArgs{chanArgs: newChanArgs(nil)}
+	for _, opt := range opts {
+		opt.applyDistribute(&args)
+	}
+
+	return args
+}
+
+// newLookupArgs applies opts over the default lookupArgs.
+func newLookupArgs(opts []LookupOpt) lookupArgs {
+	args := lookupArgs{chanArgs: newChanArgs(nil)}
+	for _, opt := range opts {
+		opt.applyLookup(&args)
+	}
+
+	return args
+}
+
+// newRandomArgs applies opts over the default randomArgs.
+func newRandomArgs(opts []RandomOpt) randomArgs {
+	args := randomArgs{
+		chanArgs: newChanArgs(nil),
+		random:   rand.Float64,
+	}
+	for _, opt := range opts {
+		opt.applyRandom(&args)
+	}
+
+	return args
+}
+
+// newChanArgs applies opts over the default chanArgs.
+func newChanArgs(opts []ChanOpt) chanArgs {
+	args := chanArgs{
+		ctx: context.Background(),
+	}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	return args
+}
+
+// withChanArgs is a ChanOpt that replaces all of
+// the chanArgs of an operation with args, so that an
+// operation can pass its own ChanOpts on to another.
+func withChanArgs(args chanArgs) ChanOpt {
+	return func(o *chanArgs) {
+		*o = args
+	}
+}
+
+// recv receives the next element from ch, reporting false
+// if ch is closed or ctx is cancelled before one arrives.
+func recv[T any](ctx context.Context, ch <-chan T) (T, bool) {
+	select {
+	case ele, ok := <-ch:
+		return ele, ok
+	case <-ctx.Done():
+		var ele T
+		return ele, false
+	}
+}
+
+// send sends ele on ch, reporting false
+// if ctx is cancelled before it is received.
+func send[T any](ctx context.Context, ch chan<- T, ele T) bool {
+	select {
+	case ch <- ele:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,1985 @@
+package chans_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcmathja/funky/chans"
+	"github.com/mcmathja/funky/pairs"
+)
+
+// collect reads ch until it closes, failing the test
+// if that takes longer than a second.
+func collect[T any](t *testing.T, ch <-chan T) []T {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	out, err := chans.Collect(ctx, ch)
+	if err != nil {
+		t.Fatalf("channel did not close: %v", err)
+	}
+
+	return out
+}
+
+// forever returns a channel that produces ele until ctx is cancelled,
+// and is never closed.
+func forever[T any](ctx context.Context, ele T) <-chan T {
+	ch := make(chan T)
+	go func() {
+		for {
+			select {
+			case ch <- ele:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// pacedStep is an element to be sent by paced after a delay.
+type pacedStep[T any] struct {
+	delay time.Duration
+	ele   T
+}
+
+// paced returns a channel that sends each element of steps
+// after its delay has elapsed, and then closes.
+func paced[T any](steps ...pacedStep[T]) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for _, step := range steps {
+			time.Sleep(step.delay)
+			ch <- step.ele
+		}
+	}()
+
+	return ch
+}
+
+// closesOnCancel checks that the output of fn closes once its context
+// is cancelled, even though its input never closes.
+func closesOnCancel[T any](t *testing.T, fn func(ctx context.Context, in <-chan int) <-chan T) {
+	t.Helper()
+
+	srcCtx, srcCancel := context.WithCancel(context.Background())
+	defer srcCancel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := fn(ctx, forever(srcCtx, 1))
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	collect(t, out)
+}
+
+func TestTopic(t *testing.T) {
+	t.Parallel()
+
+	topic := chans.NewTopic[int]()
+	a := topic.Subscribe(chans.SubscribeBuffer(3, chans.OverflowBlock))
+	b := topic.Subscribe(chans.SubscribeBuffer(3, chans.OverflowBlock))
+
+	topic.Publish(1)
+	topic.Publish(2)
+
+	topic.Unsubscribe(b)
+	topic.Publish(3)
+
+	// Unsubscribing twice, or a channel that was never subscribed, is a no-op.
+	topic.Unsubscribe(b)
+	topic.Unsubscribe(make(chan int))
+
+	topic.Close()
+	topic.Publish(4)
+
+	if out := collect(t, a); !reflect.DeepEqual(out, []int{1, 2, 3}) {
+		t.Errorf(`expected %+v to equal %+v`, out, []int{1, 2, 3})
+	}
+	if out := collect(t, b); !reflect.DeepEqual(out, []int{1, 2}) {
+		t.Errorf(`expected %+v to equal %+v`, out, []int{1, 2})
+	}
+	if out := collect(t, topic.Subscribe()); len(out) != 0 {
+		t.Errorf("expected a subscription after Close to be closed and empty, but received %+v", out)
+	}
+}
+
+func TestTopicOverflow(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		strategy chans.OverflowStrategy
+		out      []int
+	}{
+		"drop newest": {
+			strategy: chans.OverflowDropNewest,
+			out:      []int{1, 2},
+		},
+		"drop oldest": {
+			strategy: chans.OverflowDropOldest,
+			out:      []int{3, 4},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			topic := chans.NewTopic[int]()
+			sub := topic.Subscribe(chans.SubscribeBuffer(2, tc.strategy))
+			for i := 1; i <= 4; i++ {
+				topic.Publish(i)
+			}
+			topic.Close()
+
+			if out := collect(t, sub); !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestTopicUnsubscribeUnblocksPublish(t *testing.T) {
+	t.Parallel()
+
+	topic := chans.NewTopic[int]()
+	sub := topic.Subscribe()
+
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		topic.Publish(1)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	topic.Unsubscribe(sub)
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatalf("expected Unsubscribe to release a blocked Publish")
+	}
+	if out := collect(t, sub); len(out) != 0 {
+		t.Errorf("expected no elements, but received %+v", out)
+	}
+}
+
+func TestTopicConcurrent(t *testing.T) {
+	t.Parallel()
+
+	topic := chans.NewTopic[int]()
+	subs := make([]<-chan int, 5)
+	for i := range subs {
+		subs[i] = topic.Subscribe(chans.SubscribeBuffer(100, chans.OverflowBlock))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				topic.Publish(j)
+			}
+		}()
+	}
+	wg.Wait()
+	topic.Close()
+
+	for _, sub := range subs {
+		if out := collect(t, sub); len(out) != 100 {
+			t.Errorf("expected 100 elements, but received %d", len(out))
+		}
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]func(ctx context.Context, in <-chan int) <-chan int{
+		"Map": func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.Map(in, func(i int) int { return i }, chans.WithContext(ctx))
+		},
+		"Filter": func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.Filter(in, func(int) bool { return true }, chans.WithContext(ctx))
+		},
+		"Take": func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.Take(in, 1000000, chans.WithContext(ctx))
+		},
+		"MergeSlice": func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.MergeSlice([]<-chan int{in, in}, chans.WithContext(ctx))
+		},
+		"MergeRoundRobinSlice": func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.MergeRoundRobinSlice([]<-chan int{in, in}, chans.WithContext(ctx))
+		},
+		"MergeSortedSlice": func(ctx context.Context, in <-chan int) <-chan int {
+			less := func(a, b int) bool { return a < b }
+			return chans.MergeSortedSlice(less, []<-chan int{in, in}, chans.WithContext(ctx))
+		},
+	}
+
+	for name, fn := range testCases {
+		fn := fn
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srcCtx, srcCancel := context.WithCancel(context.Background())
+			defer srcCancel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			out := fn(ctx, forever(srcCtx, 1))
+
+			<-out
+			cancel()
+
+			// The output must close even though the input never does,
+			// though elements already in flight may still arrive.
+			collect(t, out)
+		})
+	}
+}
+
+func TestMergeOrdered(t *testing.T) {
+	t.Parallel()
+
+	less := func(a, b int) bool { return a < b }
+
+	testCases := map[string]struct {
+		fn  func(chs []<-chan int) <-chan int
+		out []int
+	}{
+		"MergeRoundRobin": {
+			fn:  func(chs []<-chan int) <-chan int { return chans.MergeRoundRobin(chs...) },
+			out: []int{1, 2, 7, 3, 4, 8, 5, 9},
+		},
+		"MergeRoundRobinSlice": {
+			fn:  func(chs []<-chan int) <-chan int { return chans.MergeRoundRobinSlice(chs, chans.WithCapacity(2)) },
+			out: []int{1, 2, 7, 3, 4, 8, 5, 9},
+		},
+		"MergeSorted": {
+			fn:  func(chs []<-chan int) <-chan int { return chans.MergeSorted(less, chs...) },
+			out: []int{1, 2, 3, 4, 5, 7, 8, 9},
+		},
+		"MergeSortedSlice": {
+			fn:  func(chs []<-chan int) <-chan int { return chans.MergeSortedSlice(less, chs, chans.WithCapacity(2)) },
+			out: []int{1, 2, 3, 4, 5, 7, 8, 9},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			chs := []<-chan int{chans.New(1, 3, 5), chans.New(2, 4), chans.New[int](), chans.New(7, 8, 9)}
+			out := collect(t, tc.fn(chs))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  []int
+		out []int
+	}{
+		"simple case": {
+			in:  []int{1, 2, 3},
+			out: []int{1, 2, 3},
+		},
+		"no elements": {
+			in:  nil,
+			out: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := collect(t, chans.New(tc.in...))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestConstructorsWithContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]func(opts ...chans.ChanOpt) <-chan int{
+		"FromSlice": func(opts ...chans.ChanOpt) <-chan int {
+			return chans.FromSlice([]int{1, 2, 3}, opts...)
+		},
+		"FromSet": func(opts ...chans.ChanOpt) <-chan int {
+			return chans.FromSet(map[int]struct{}{1: {}, 2: {}}, opts...)
+		},
+		"FromFunc": func(opts ...chans.ChanOpt) <-chan int {
+			return chans.FromFunc(func() int { return 1 }, opts...)
+		},
+		"FromBatch": func(opts ...chans.ChanOpt) <-chan int {
+			return chans.FromBatch(func(next func(int)) {
+				for i := 0; i < 3; i++ {
+					next(i)
+				}
+			}, opts...)
+		},
+		"Interval": func(opts ...chans.ChanOpt) <-chan int {
+			return chans.Interval(time.Millisecond, opts...)
+		},
+	}
+
+	for name, fn := range testCases {
+		fn := fn
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			// Nothing reads from the output until the context is
+			// cancelled, so the producer must give up and close it.
+			out := fn(chans.WithContext(ctx))
+			time.Sleep(10 * time.Millisecond)
+
+			if rest := collect(t, out); len(rest) > 1 {
+				t.Errorf("expected at most one element after cancellation, but received %+v", rest)
+			}
+		})
+	}
+}
+
+func TestFromSliceWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := chans.FromSlice([]int{1, 2, 3}, chans.WithContext(ctx))
+
+	<-out
+	cancel()
+
+	if rest := collect(t, out); len(rest) > 1 {
+		t.Errorf("expected at most one element after cancellation, but received %+v", rest)
+	}
+}
+
+func TestWithCapacity(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts     []chans.ChanOpt
+		capacity int
+	}{
+		"default": {
+			capacity: 0,
+		},
+		"buffered": {
+			opts:     []chans.ChanOpt{chans.WithCapacity(3)},
+			capacity: 3,
+		},
+		"negative": {
+			opts:     []chans.ChanOpt{chans.WithCapacity(-1)},
+			capacity: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Slices are not comparable, so this also checks
+			// that FromSlice accepts any element type.
+			in := [][]int{{1}, {2, 3}, {}}
+			src := chans.FromSlice(in, tc.opts...)
+			out := chans.Map(src, func(s []int) int { return len(s) }, tc.opts...)
+
+			if cap(src) != tc.capacity || cap(out) != tc.capacity {
+				t.Errorf(`expected capacities %d and %d to equal %d`, cap(src), cap(out), tc.capacity)
+			}
+			if res := collect(t, out); !reflect.DeepEqual(res, []int{1, 2, 0}) {
+				t.Errorf(`expected %+v to equal %+v`, res, []int{1, 2, 0})
+			}
+		})
+	}
+}
+
+func TestFirstAndLast(t *testing.T) {
+	t.Parallel()
+
+	even := func(i int) bool { return i%2 == 0 }
+
+	testCases := map[string]struct {
+		in           []int
+		first        int
+		hasFirst     bool
+		firstEven    int
+		hasFirstEven bool
+		last         int
+		hasLast      bool
+		lastEven     int
+		hasLastEven  bool
+	}{
+		"simple case": {
+			in:           []int{1, 2, 3, 4, 5},
+			first:        1,
+			hasFirst:     true,
+			firstEven:    2,
+			hasFirstEven: true,
+			last:         5,
+			hasLast:      true,
+			lastEven:     4,
+			hasLastEven:  true,
+		},
+		"no matches": {
+			in:       []int{1, 3},
+			first:    1,
+			hasFirst: true,
+			last:     3,
+			hasLast:  true,
+		},
+		"zero value element": {
+			in:           []int{0},
+			first:        0,
+			hasFirst:     true,
+			firstEven:    0,
+			hasFirstEven: true,
+			last:         0,
+			hasLast:      true,
+			lastEven:     0,
+			hasLastEven:  true,
+		},
+		"empty input": {
+			in: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out, ok := chans.First(chans.New(tc.in...)); out != tc.first || ok != tc.hasFirst {
+				t.Errorf("expected First %d, %t, but received %d, %t", tc.first, tc.hasFirst, out, ok)
+			}
+			if out, ok := chans.FirstWhere(chans.New(tc.in...), even); out != tc.firstEven || ok != tc.hasFirstEven {
+				t.Errorf("expected FirstWhere %d, %t, but received %d, %t", tc.firstEven, tc.hasFirstEven, out, ok)
+			}
+			if out, ok := chans.Last(chans.New(tc.in...)); out != tc.last || ok != tc.hasLast {
+				t.Errorf("expected Last %d, %t, but received %d, %t", tc.last, tc.hasLast, out, ok)
+			}
+			if out, ok := chans.LastWhere(chans.New(tc.in...), even); out != tc.lastEven || ok != tc.hasLastEven {
+				t.Errorf("expected LastWhere %d, %t, but received %d, %t", tc.lastEven, tc.hasLastEven, out, ok)
+			}
+		})
+	}
+}
+
+func TestFirstAndLastTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	never := make(chan int)
+	if _, ok := chans.First(never, chans.WithTimeout(10*time.Millisecond)); ok {
+		t.Errorf("expected First to give up once the timeout expires")
+	}
+	if _, ok := chans.Last(forever(ctx, 1), chans.WithTimeout(10*time.Millisecond)); ok {
+		t.Errorf("expected Last to give up once the timeout expires")
+	}
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, ok := chans.FirstWhere(never, func(int) bool { return true }, chans.WithContext(cancelled)); ok {
+		t.Errorf("expected FirstWhere to give up once the context is cancelled")
+	}
+}
+
+func TestLookupOpts(t *testing.T) {
+	t.Parallel()
+
+	opts := []chans.LookupOpt{chans.WithContext(context.Background()), chans.WithTimeout(time.Second)}
+
+	if ele, ok := chans.First(chans.New(1, 2, 3), opts...); !ok || ele != 1 {
+		t.Errorf("expected First to return 1, but got %+v, %t", ele, ok)
+	}
+	if ele, ok := chans.LastWhere(chans.New(1, 2, 3), func(i int) bool { return i < 3 }, opts...); !ok || ele != 2 {
+		t.Errorf("expected LastWhere to return 2, but got %+v, %t", ele, ok)
+	}
+}
+
+func TestTryTransforms(t *testing.T) {
+	t.Parallel()
+
+	bad := errors.New("bad")
+	failOn := func(n int) func(int) error {
+		return func(i int) error {
+			if i == n {
+				return bad
+			}
+			return nil
+		}
+	}
+
+	testCases := map[string]struct {
+		fn  func(in <-chan int) (<-chan int, <-chan error)
+		out []int
+		err error
+	}{
+		"TryMap success": {
+			fn: func(in <-chan int) (<-chan int, <-chan error) {
+				return chans.TryMap(in, func(i int) (int, error) { return i * 2, nil })
+			},
+			out: []int{2, 4, 6, 8},
+		},
+		"TryMap error": {
+			fn: func(in <-chan int) (<-chan int, <-chan error) {
+				return chans.TryMap(in, func(i int) (int, error) { return i * 2, failOn(3)(i) })
+			},
+			out: []int{2, 4},
+			err: bad,
+		},
+		"TryFilter success": {
+			fn: func(in <-chan int) (<-chan int, <-chan error) {
+				return chans.TryFilter(in, func(i int) (bool, error) { return i%2 == 0, nil })
+			},
+			out: []int{2, 4},
+		},
+		"TryFilter error": {
+			fn: func(in <-chan int) (<-chan int, <-chan error) {
+				return chans.TryFilter(in, func(i int) (bool, error) { return true, failOn(2)(i) })
+			},
+			out: []int{1},
+			err: bad,
+		},
+		"PartitionErrors error": {
+			fn: func(in <-chan int) (<-chan int, <-chan error) {
+				return chans.PartitionErrors(chans.Map(in, func(i int) pairs.Pair[int, error] {
+					return pairs.New(i, failOn(4)(i))
+				}))
+			},
+			out: []int{1, 2, 3},
+			err: bad,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			values, errs := tc.fn(chans.New(1, 2, 3, 4))
+
+			// Reading the values to completion before the
+			// errors must not deadlock on the first error.
+			if out := collect(t, values); !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+
+			errOut := collect(t, errs)
+			if tc.err == nil && len(errOut) != 0 {
+				t.Errorf("expected no errors, but received %+v", errOut)
+			}
+			if tc.err != nil && (len(errOut) != 1 || errOut[0] != tc.err) {
+				t.Errorf("expected exactly %v, but received %+v", tc.err, errOut)
+			}
+		})
+	}
+}
+
+func TestSlidingWindowByTimeFlushesOnClose(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fn func(ch <-chan int) <-chan []int
+	}{
+		"WindowByTime": {
+			fn: func(ch <-chan int) <-chan []int { return chans.WindowByTime(ch, time.Hour) },
+		},
+		"SlidingWindowByTime": {
+			fn: func(ch <-chan int) <-chan []int { return chans.SlidingWindowByTime(ch, time.Hour, time.Hour) },
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := collect(t, tc.fn(chans.New(1, 2, 3)))
+
+			if !reflect.DeepEqual(out, [][]int{{1, 2, 3}}) {
+				t.Errorf(`expected %+v to equal %+v`, out, [][]int{{1, 2, 3}})
+			}
+		})
+	}
+}
+
+func TestSlidingWindowByTimeNoDuplicateOnClose(t *testing.T) {
+	t.Parallel()
+
+	in := make(chan int)
+	out := chans.SlidingWindowByTime(in, time.Hour, 20*time.Millisecond)
+
+	in <- 1
+	first := <-out
+	close(in)
+
+	rest := collect(t, out)
+	if !reflect.DeepEqual(first, []int{1}) || len(rest) != 0 {
+		t.Errorf("expected a single window [1], but received %+v then %+v", first, rest)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	backoff := chans.RetryBackoff(time.Millisecond, time.Millisecond)
+
+	testCases := map[string]struct {
+		results []error
+		opts    []chans.RetryOpt
+		out     []int
+		calls   int
+	}{
+		"succeeds first time": {
+			results: []error{nil},
+			opts:    []chans.RetryOpt{backoff},
+			out:     []int{1, 2},
+			calls:   1,
+		},
+		"succeeds after failures": {
+			results: []error{errors.New("a"), errors.New("b"), nil},
+			opts:    []chans.RetryOpt{backoff},
+			out:     []int{1, 2},
+			calls:   3,
+		},
+		"attempts exhausted": {
+			results: []error{errors.New("a"), errors.New("b"), nil},
+			opts:    []chans.RetryOpt{backoff, chans.RetryAttempts(2)},
+			out:     []int{},
+			calls:   2,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+			fn := func() (<-chan int, error) {
+				err := tc.results[calls]
+				calls++
+				if err != nil {
+					return nil, err
+				}
+				return chans.New(1, 2), nil
+			}
+
+			out := collect(t, chans.RetryWith(fn, tc.opts, chans.WithCapacity(2)))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if calls != tc.calls {
+				t.Errorf(`expected %d calls, but got %d`, tc.calls, calls)
+			}
+		})
+	}
+}
+
+func TestRetryWithContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fn := func() (<-chan int, error) {
+		return nil, errors.New("unavailable")
+	}
+	out := chans.RetryWith(fn, []chans.RetryOpt{chans.RetryAttempts(0)}, chans.WithContext(ctx))
+
+	cancel()
+
+	if rest := collect(t, out); len(rest) != 0 {
+		t.Errorf("expected no elements, but received %+v", rest)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	t.Parallel()
+
+	const d = 30 * time.Millisecond
+	const gap = 4 * d
+
+	burst := []pacedStep[int]{{0, 1}, {0, 2}, {0, 3}}
+	bursts := []pacedStep[int]{{0, 1}, {0, 2}, {gap, 3}, {0, 4}}
+
+	testCases := map[string]struct {
+		fn  func(ch <-chan int) <-chan int
+		in  []pacedStep[int]
+		out []int
+	}{
+		"trailing with one burst": {
+			fn:  func(ch <-chan int) <-chan int { return chans.Debounce(ch, d) },
+			in:  burst,
+			out: []int{3},
+		},
+		"trailing with separate bursts": {
+			fn:  func(ch <-chan int) <-chan int { return chans.Debounce(ch, d) },
+			in:  bursts,
+			out: []int{2, 4},
+		},
+		"leading with one burst": {
+			fn:  func(ch <-chan int) <-chan int { return chans.DebounceLeading(ch, d) },
+			in:  burst,
+			out: []int{1},
+		},
+		"leading with separate bursts": {
+			fn:  func(ch <-chan int) <-chan int { return chans.DebounceLeading(ch, d) },
+			in:  bursts,
+			out: []int{1, 3},
+		},
+		"empty input": {
+			fn:  func(ch <-chan int) <-chan int { return chans.Debounce(ch, d) },
+			in:  nil,
+			out: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := collect(t, tc.fn(paced(tc.in...)))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.Debounce(in, time.Hour, chans.WithContext(ctx))
+		})
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.DebounceLeading(in, time.Hour, chans.WithContext(ctx))
+		})
+	})
+}
+
+func TestBufferTimeout(t *testing.T) {
+	t.Parallel()
+
+	const maxWait = 30 * time.Millisecond
+	const gap = 4 * maxWait
+
+	testCases := map[string]struct {
+		size int
+		in   []pacedStep[int]
+		out  [][]int
+	}{
+		"flushes when full": {
+			size: 2,
+			in:   []pacedStep[int]{{0, 1}, {0, 2}, {0, 3}, {0, 4}, {0, 5}},
+			out:  [][]int{{1, 2}, {3, 4}, {5}},
+		},
+		"flushes after maxWait": {
+			size: 10,
+			in:   []pacedStep[int]{{0, 1}, {0, 2}, {gap, 3}},
+			out:  [][]int{{1, 2}, {3}},
+		},
+		"restarts the wait after flushing when full": {
+			size: 2,
+			in:   []pacedStep[int]{{0, 1}, {0, 2}, {0, 3}, {gap, 4}},
+			out:  [][]int{{1, 2}, {3}, {4}},
+		},
+		"empty input": {
+			size: 2,
+			in:   nil,
+			out:  [][]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := collect(t, chans.BufferTimeout(paced(tc.in...), tc.size, maxWait))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan []int {
+			return chans.BufferTimeout(in, 2, time.Hour, chans.WithContext(ctx))
+		})
+	})
+}
+
+// gauge tracks how many calls are in progress at once.
+type gauge struct {
+	mu      sync.Mutex
+	active  int
+	highest int
+}
+
+// enter records the start of a call, returning a
+// function that records its end.
+func (g *gauge) enter() func() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.active++
+	if g.active > g.highest {
+		g.highest = g.active
+	}
+
+	return func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.active--
+	}
+}
+
+func TestMapConcurrent(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		workers int
+		opts    []chans.ChanOpt
+		ordered bool
+		highest int
+	}{
+		"unordered": {
+			workers: 4,
+			highest: 4,
+		},
+		"ordered": {
+			workers: 4,
+			opts:    []chans.ChanOpt{chans.PreserveOrder},
+			ordered: true,
+			highest: 4,
+		},
+		"ordered and buffered": {
+			workers: 3,
+			opts:    []chans.ChanOpt{chans.PreserveOrder, chans.WithCapacity(5)},
+			ordered: true,
+			highest: 3,
+		},
+		"non-positive workers": {
+			workers: 0,
+			opts:    []chans.ChanOpt{chans.PreserveOrder},
+			ordered: true,
+			highest: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Earlier elements take longer, so that
+			// results finish out of their input order.
+			g := &gauge{}
+			fn := func(i int) int {
+				defer g.enter()()
+				time.Sleep(time.Duration(12-i) * time.Millisecond)
+				return i * 10
+			}
+
+			in := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+			out := collect(t, chans.MapConcurrent(chans.FromSlice(in), fn, tc.workers, tc.opts...))
+
+			want := []int{0, 10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 110}
+			if !tc.ordered {
+				sort.Ints(out)
+			}
+			if !reflect.DeepEqual(out, want) {
+				t.Errorf(`expected %+v to equal %+v`, out, want)
+			}
+			if g.highest != tc.highest {
+				t.Errorf(`expected a peak of %d concurrent calls, but got %d`, tc.highest, g.highest)
+			}
+		})
+	}
+
+	for name, opts := range map[string][]chans.ChanOpt{
+		"unordered cancellation": nil,
+		"ordered cancellation":   {chans.PreserveOrder},
+	} {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+				return chans.MapConcurrent(in, func(i int) int { return i }, 3, append(opts, chans.WithContext(ctx))...)
+			})
+		})
+	}
+}
+
+func TestFilterConcurrent(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts    []chans.ChanOpt
+		ordered bool
+	}{
+		"unordered": {},
+		"ordered": {
+			opts:    []chans.ChanOpt{chans.PreserveOrder},
+			ordered: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			g := &gauge{}
+			even := func(i int) bool {
+				defer g.enter()()
+				time.Sleep(time.Duration(12-i) * time.Millisecond)
+				return i%2 == 0
+			}
+
+			in := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+			out := collect(t, chans.FilterConcurrent(chans.FromSlice(in), even, 3, tc.opts...))
+
+			if !tc.ordered {
+				sort.Ints(out)
+			}
+			if want := []int{0, 2, 4, 6, 8, 10}; !reflect.DeepEqual(out, want) {
+				t.Errorf(`expected %+v to equal %+v`, out, want)
+			}
+			if g.highest != 3 {
+				t.Errorf(`expected a peak of 3 concurrent calls, but got %d`, g.highest)
+			}
+		})
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.FilterConcurrent(in, func(int) bool { return true }, 3, chans.WithContext(ctx))
+		})
+	})
+}
+
+func TestForEachConcurrent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("visits every element", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		g := &gauge{}
+		seen := []int{}
+		chans.ForEachConcurrent(chans.New(5, 4, 3, 2, 1, 0), func(i int) {
+			defer g.enter()()
+			time.Sleep(time.Duration(i) * time.Millisecond)
+			mu.Lock()
+			seen = append(seen, i)
+			mu.Unlock()
+		}, 2)
+
+		// ForEachConcurrent must not return before every call has completed.
+		sort.Ints(seen)
+		if want := []int{0, 1, 2, 3, 4, 5}; !reflect.DeepEqual(seen, want) {
+			t.Errorf(`expected %+v to equal %+v`, seen, want)
+		}
+		if g.highest != 2 {
+			t.Errorf(`expected a peak of 2 concurrent calls, but got %d`, g.highest)
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		srcCtx, srcCancel := context.WithCancel(context.Background())
+		defer srcCancel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		returned := make(chan struct{})
+		go func() {
+			defer close(returned)
+			chans.ForEachConcurrent(forever(srcCtx, 1), func(int) {}, 3, chans.WithContext(ctx))
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-returned:
+		case <-time.After(time.Second):
+			t.Fatalf("expected ForEachConcurrent to return once cancelled")
+		}
+	})
+}
+
+func TestInterval(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := chans.Interval(5*time.Millisecond, chans.WithContext(ctx))
+
+	out := []int{<-ticks, <-ticks, <-ticks}
+	cancel()
+
+	if !reflect.DeepEqual(out, []int{0, 1, 2}) {
+		t.Errorf(`expected %+v to equal %+v`, out, []int{0, 1, 2})
+	}
+
+	// The output must close once cancelled, though
+	// a tick already in flight may still arrive.
+	if rest := collect(t, ticks); len(rest) > 1 {
+		t.Errorf("expected at most one tick after cancellation, but received %+v", rest)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		d         time.Duration
+		cancelled bool
+		fired     int
+	}{
+		"fires once": {
+			d:     5 * time.Millisecond,
+			fired: 1,
+		},
+		"cancelled before firing": {
+			d:         time.Hour,
+			cancelled: true,
+			fired:     0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancelled {
+				cancel()
+			}
+
+			start := time.Now()
+			out := collect(t, chans.Timer(tc.d, chans.WithContext(ctx)))
+
+			if len(out) != tc.fired {
+				t.Errorf(`expected %d values, but received %d`, tc.fired, len(out))
+			}
+			if tc.fired > 0 && time.Since(start) < tc.d {
+				t.Errorf("expected the timer not to fire before %v", tc.d)
+			}
+		})
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	t.Parallel()
+
+	const ttl = 30 * time.Millisecond
+	const gap = 4 * ttl
+
+	testCases := map[string]struct {
+		in   []pacedStep[int]
+		opts []chans.DistinctOpt
+		out  []int
+	}{
+		"unbounded": {
+			in:  []pacedStep[int]{{0, 1}, {0, 2}, {0, 1}, {0, 3}, {0, 2}},
+			out: []int{1, 2, 3},
+		},
+		"max size evicts the least recently seen key": {
+			in:   []pacedStep[int]{{0, 1}, {0, 2}, {0, 1}, {0, 3}, {0, 2}, {0, 1}},
+			opts: []chans.DistinctOpt{chans.DistinctMaxSize(2)},
+			out:  []int{1, 2, 3, 2, 1},
+		},
+		"ttl forgets keys not seen recently": {
+			in:   []pacedStep[int]{{0, 1}, {0, 1}, {gap, 1}, {0, 2}},
+			opts: []chans.DistinctOpt{chans.DistinctTTL(ttl)},
+			out:  []int{1, 1, 2},
+		},
+		"accepts ChanOpts": {
+			in:   []pacedStep[int]{{0, 1}, {0, 1}, {0, 2}},
+			opts: []chans.DistinctOpt{chans.WithCapacity(2), chans.DistinctMaxSize(1)},
+			out:  []int{1, 2},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := collect(t, chans.Distinct(paced(tc.in...), tc.opts...))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+
+	t.Run("by key", func(t *testing.T) {
+		t.Parallel()
+
+		out := collect(t, chans.DistinctBy(chans.New("a", "bb", "c", "dd", "eee"), func(s string) int {
+			return len(s)
+		}))
+
+		if want := []string{"a", "bb", "eee"}; !reflect.DeepEqual(out, want) {
+			t.Errorf(`expected %+v to equal %+v`, out, want)
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.Distinct(in, chans.WithContext(ctx), chans.DistinctMaxSize(1))
+		})
+	})
+}
+
+func TestSampleEvery(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		n   int
+		out []int
+	}{
+		"every third": {
+			n:   3,
+			out: []int{3, 6, 9},
+		},
+		"every element": {
+			n:   1,
+			out: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		"more than the input": {
+			n:   11,
+			out: []int{},
+		},
+		"non-positive": {
+			n:   0,
+			out: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := collect(t, chans.SampleEvery(chans.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), tc.n))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestSampleRate(t *testing.T) {
+	t.Parallel()
+
+	draws := []float64{0.1, 0.9, 0.5, 0.49, 0.0, 0.99}
+
+	testCases := map[string]struct {
+		p   float64
+		out []int
+	}{
+		"half": {
+			p:   0.5,
+			out: []int{1, 4, 5},
+		},
+		"always": {
+			p:   1,
+			out: []int{1, 2, 3, 4, 5, 6},
+		},
+		"never": {
+			p:   0,
+			out: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			idx := 0
+			random := func() float64 {
+				draw := draws[idx]
+				idx++
+				return draw
+			}
+
+			in := chans.New(1, 2, 3, 4, 5, 6)
+			out := collect(t, chans.SampleRate(in, tc.p, chans.WithRandom(random), chans.WithCapacity(1)))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.SampleRate(in, 1, chans.WithContext(ctx))
+		})
+	})
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		n    int
+		live []int
+		late []int
+	}{
+		"unbounded history": {
+			n:    0,
+			live: []int{1, 2, 3, 4},
+			late: []int{1, 2, 3, 4},
+		},
+		"bounded history": {
+			n:    2,
+			live: []int{1, 2, 3, 4},
+			late: []int{3, 4},
+		},
+		"history longer than the input": {
+			n:    10,
+			live: []int{1, 2, 3, 4},
+			late: []int{1, 2, 3, 4},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			in := make(chan int)
+			subscribe := chans.Replay(in, tc.n)
+
+			// A subscriber from the start that keeps up receives
+			// every element, even beyond the recorded history.
+			live := subscribe()
+			out := []int{}
+			for i := 1; i <= 4; i++ {
+				in <- i
+				out = append(out, <-live)
+			}
+			close(in)
+			out = append(out, collect(t, live)...)
+			if !reflect.DeepEqual(out, tc.live) {
+				t.Errorf(`expected live %+v to equal %+v`, out, tc.live)
+			}
+
+			// Once the input has closed, a new subscriber
+			// receives only the recorded history.
+			if out := collect(t, subscribe()); !reflect.DeepEqual(out, tc.late) {
+				t.Errorf(`expected late %+v to equal %+v`, out, tc.late)
+			}
+		})
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		srcCtx, srcCancel := context.WithCancel(context.Background())
+		defer srcCancel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		subscribe := chans.Replay(forever(srcCtx, 1), 3, chans.WithContext(ctx))
+
+		// One subscriber is blocked sending to its consumer,
+		// and the other only subscribes after cancellation.
+		blocked := subscribe()
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		collect(t, blocked)
+		collect(t, subscribe())
+	})
+}
+
+// collectAll reads every channel in chs concurrently until they
+// all close, failing the test if that takes longer than a second.
+func collectAll[T any](t *testing.T, chs []<-chan T) [][]T {
+	t.Helper()
+
+	out := make([][]T, len(chs))
+	var wg sync.WaitGroup
+	for idx, ch := range chs {
+		wg.Add(1)
+		go func(idx int, ch <-chan T) {
+			defer wg.Done()
+			out[idx], _ = chans.Collect(context.Background(), ch)
+		}(idx, ch)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("channels did not close")
+	}
+
+	return out
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in   []string
+		opts []chans.ChanOpt
+		out  map[int][]string
+		keys []int
+	}{
+		"simple case": {
+			in:   []string{"a", "bb", "c", "ddd", "ee", "f"},
+			out:  map[int][]string{1: {"a", "c", "f"}, 2: {"bb", "ee"}, 3: {"ddd"}},
+			keys: []int{1, 2, 3},
+		},
+		"buffered": {
+			in:   []string{"a", "bb", "c"},
+			opts: []chans.ChanOpt{chans.WithCapacity(4)},
+			out:  map[int][]string{1: {"a", "c"}, 2: {"bb"}},
+			keys: []int{1, 2},
+		},
+		"empty input": {
+			in:   nil,
+			out:  map[int][]string{},
+			keys: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			groups := chans.GroupBy(chans.FromSlice(tc.in), func(s string) int { return len(s) }, tc.opts...)
+
+			var mu sync.Mutex
+			var wg sync.WaitGroup
+			out := map[int][]string{}
+			keys := []int{}
+			for group := range groups {
+				keys = append(keys, group.Left)
+				wg.Add(1)
+				go func(k int, ch <-chan string) {
+					defer wg.Done()
+					ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+					defer cancel()
+					eles, err := chans.Collect(ctx, ch)
+					if err != nil {
+						t.Errorf("group %d did not close: %v", k, err)
+					}
+					mu.Lock()
+					out[k] = eles
+					mu.Unlock()
+				}(group.Left, group.Right)
+			}
+			wg.Wait()
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if !reflect.DeepEqual(keys, tc.keys) {
+				t.Errorf(`expected keys %+v to equal %+v`, keys, tc.keys)
+			}
+		})
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan pairs.Pair[int, <-chan int] {
+			return chans.GroupBy(in, func(i int) int { return i }, chans.WithContext(ctx))
+		})
+	})
+}
+
+func TestPartitionN(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fn  func(ch <-chan int) []<-chan int
+		out [][]int
+	}{
+		"round robin": {
+			fn:  func(ch <-chan int) []<-chan int { return chans.PartitionN(ch, 3) },
+			out: [][]int{{1, 4, 7}, {2, 5}, {3, 6}},
+		},
+		"single partition": {
+			fn:  func(ch <-chan int) []<-chan int { return chans.PartitionN(ch, 1) },
+			out: [][]int{{1, 2, 3, 4, 5, 6, 7}},
+		},
+		"by hash": {
+			fn: func(ch <-chan int) []<-chan int {
+				return chans.PartitionByHash(ch, 2, func(i int) uint64 { return uint64(i / 3) })
+			},
+			out: [][]int{{1, 2, 6, 7}, {3, 4, 5}},
+		},
+		"no partitions": {
+			fn:  func(ch <-chan int) []<-chan int { return chans.PartitionN(ch, 0) },
+			out: [][]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := collectAll(t, tc.fn(chans.New(1, 2, 3, 4, 5, 6, 7)))
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		srcCtx, srcCancel := context.WithCancel(context.Background())
+		defer srcCancel()
+
+		// Only the first partition is read, so the
+		// second blocks until the context is cancelled.
+		ctx, cancel := context.WithCancel(context.Background())
+		outs := chans.PartitionN(forever(srcCtx, 1), 2, chans.WithContext(ctx))
+		<-outs[0]
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+
+		collectAll(t, outs)
+	})
+}
+
+func TestSorted(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		key   int
+		label string
+	}
+	in := []item{{3, "a"}, {1, "b"}, {2, "c"}, {1, "d"}, {3, "e"}}
+	byKey := func(a, b item) bool { return a.key < b.key }
+
+	testCases := map[string]struct {
+		fn  func(ch <-chan item) (<-chan item, <-chan error)
+		out []item
+		err bool
+	}{
+		"SortedBy is stable": {
+			fn: func(ch <-chan item) (<-chan item, <-chan error) {
+				return chans.SortedBy(ch, byKey), nil
+			},
+			out: []item{{1, "b"}, {1, "d"}, {2, "c"}, {3, "a"}, {3, "e"}},
+		},
+		"TrySortedBy within bound": {
+			fn: func(ch <-chan item) (<-chan item, <-chan error) {
+				return chans.TrySortedBy(ch, byKey, 5)
+			},
+			out: []item{{1, "b"}, {1, "d"}, {2, "c"}, {3, "a"}, {3, "e"}},
+		},
+		"TrySortedBy unbounded": {
+			fn: func(ch <-chan item) (<-chan item, <-chan error) {
+				return chans.TrySortedBy(ch, byKey, 0)
+			},
+			out: []item{{1, "b"}, {1, "d"}, {2, "c"}, {3, "a"}, {3, "e"}},
+		},
+		"TrySortedBy over bound": {
+			fn: func(ch <-chan item) (<-chan item, <-chan error) {
+				return chans.TrySortedBy(ch, byKey, 4)
+			},
+			out: []item{},
+			err: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			values, errs := tc.fn(chans.FromSlice(in))
+			out := collect(t, values)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if errs != nil {
+				if err := <-errs; (err != nil) != tc.err {
+					t.Errorf("expected an error to be %t, but got %v", tc.err, err)
+				}
+			}
+		})
+	}
+
+	t.Run("ordered", func(t *testing.T) {
+		t.Parallel()
+
+		out := collect(t, chans.Sorted(chans.New(5, 3, 4, 1, 2)))
+		if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(out, want) {
+			t.Errorf(`expected %+v to equal %+v`, out, want)
+		}
+
+		values, errs := chans.TrySorted(chans.New(5, 3, 4, 1, 2), 2)
+		if out := collect(t, values); len(out) != 0 {
+			t.Errorf("expected no elements once the bound is exceeded, but received %+v", out)
+		}
+		if err := <-errs; err == nil {
+			t.Errorf("expected an error once the bound is exceeded")
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.Sorted(in, chans.WithContext(ctx))
+		})
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			values, _ := chans.TrySorted(in, 1000000, chans.WithContext(ctx))
+			return values
+		})
+	})
+}
+
+func TestBufferWithOverflow(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		capacity int
+		strategy chans.OverflowStrategy
+		out      []int
+	}{
+		"block": {
+			capacity: 2,
+			strategy: chans.OverflowBlock,
+			out:      []int{1, 2, 3, 4, 5},
+		},
+		"drop newest": {
+			capacity: 2,
+			strategy: chans.OverflowDropNewest,
+			out:      []int{1, 2},
+		},
+		"drop oldest": {
+			capacity: 2,
+			strategy: chans.OverflowDropOldest,
+			out:      []int{4, 5},
+		},
+		"non-positive capacity": {
+			capacity: 0,
+			strategy: chans.OverflowDropOldest,
+			out:      []int{5},
+		},
+		"capacity exceeding the input": {
+			capacity: 10,
+			strategy: chans.OverflowDropNewest,
+			out:      []int{1, 2, 3, 4, 5},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			in := make(chan int, 5)
+			for i := 1; i <= 5; i++ {
+				in <- i
+			}
+			close(in)
+
+			out := chans.BufferWithOverflow(in, tc.capacity, tc.strategy)
+
+			// Nothing is read until the input has been consumed,
+			// or until the operator is blocked waiting for room.
+			deadline := time.Now().Add(time.Second)
+			for len(in) > 0 && tc.strategy != chans.OverflowBlock && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+
+			if res := collect(t, out); !reflect.DeepEqual(res, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, res, tc.out)
+			}
+		})
+	}
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		for _, strategy := range []chans.OverflowStrategy{chans.OverflowBlock, chans.OverflowDropNewest, chans.OverflowDropOldest} {
+			strategy := strategy
+			closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+				return chans.BufferWithOverflow(in, 2, strategy, chans.WithContext(ctx))
+			})
+		}
+	})
+}
+
+func TestSwitchMapAndExhaustMap(t *testing.T) {
+	t.Parallel()
+
+	// recvOne reads a single element from ch, failing the
+	// test if none arrives within a second.
+	recvOne := func(t *testing.T, ch <-chan int) int {
+		t.Helper()
+
+		select {
+		case ele := <-ch:
+			return ele
+		case <-time.After(time.Second):
+			t.Fatalf("expected an element")
+			return 0
+		}
+	}
+
+	t.Run("SwitchMap abandons the previous inner channel", func(t *testing.T) {
+		t.Parallel()
+
+		inners := map[int]chan int{1: make(chan int), 2: make(chan int)}
+		outer := make(chan int)
+		out := chans.SwitchMap((<-chan int)(outer), func(i int) <-chan int { return inners[i] })
+
+		outer <- 1
+		inners[1] <- 10
+		first := recvOne(t, out)
+
+		// Once 2 arrives, elements of the first inner
+		// channel are drained and discarded.
+		outer <- 2
+		inners[1] <- 11
+		inners[2] <- 20
+		second := recvOne(t, out)
+
+		close(inners[1])
+		close(inners[2])
+		close(outer)
+
+		if rest := collect(t, out); first != 10 || second != 20 || len(rest) != 0 {
+			t.Errorf("expected 10 then 20, but received %d, %d then %+v", first, second, rest)
+		}
+	})
+
+	t.Run("ExhaustMap ignores elements while an inner channel is open", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		called := []int{}
+		inners := map[int]chan int{1: make(chan int), 3: make(chan int)}
+		outer := make(chan int)
+		out := chans.ExhaustMap((<-chan int)(outer), func(i int) <-chan int {
+			mu.Lock()
+			called = append(called, i)
+			mu.Unlock()
+			return inners[i]
+		})
+
+		outer <- 1
+		outer <- 2
+		inners[1] <- 10
+		first := recvOne(t, out)
+		close(inners[1])
+
+		// Give the operator time to see the inner channel close.
+		time.Sleep(10 * time.Millisecond)
+		outer <- 3
+		inners[3] <- 30
+		second := recvOne(t, out)
+
+		close(inners[3])
+		close(outer)
+
+		if rest := collect(t, out); first != 10 || second != 30 || len(rest) != 0 {
+			t.Errorf("expected 10 then 30, but received %d, %d then %+v", first, second, rest)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(called, []int{1, 3}) {
+			t.Errorf(`expected fn to be called with %+v, but got %+v`, []int{1, 3}, called)
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		srcCtx, srcCancel := context.WithCancel(context.Background())
+		defer srcCancel()
+
+		fn := func(i int) <-chan int { return forever(srcCtx, i) }
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.SwitchMap(in, fn, chans.WithContext(ctx))
+		})
+		closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+			return chans.ExhaustMap(in, fn, chans.WithContext(ctx))
+		})
+	})
+}
+
+func TestDistribute(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts []chans.DistributeOpt
+		// out is what each output holds when output 0
+		// is read to completion before output 1.
+		out [2][]int
+		// depths is the sequence of depths reported for each output,
+		// or nil if DistributeOnDepth is not given.
+		depths [2][]int
+		// primed is the element whose arrival means that
+		// every output's queue is full.
+		primed int
+	}{
+		"least loaded": {
+			opts:   []chans.DistributeOpt{chans.DistributeLeastLoaded()},
+			out:    [2][]int{{1, 3, 4, 5, 6}, {2}},
+			primed: 3,
+		},
+		"least loaded with capacity": {
+			opts:   []chans.DistributeOpt{chans.DistributeLeastLoaded(), chans.WithCapacity(2)},
+			out:    [2][]int{{1, 3, 5, 6}, {2, 4}},
+			primed: 5,
+		},
+		"on depth": {
+			opts:   []chans.DistributeOpt{chans.DistributeLeastLoaded(), chans.DistributeOnDepth(nil)},
+			out:    [2][]int{{1, 3, 4, 5, 6}, {2}},
+			primed: 3,
+			depths: [2][]int{
+				{1, 0, 1, 0, 1, 0, 1, 0, 1, 0},
+				{1, 0},
+			},
+		},
+		"on depth implies least loaded": {
+			opts:   []chans.DistributeOpt{chans.DistributeOnDepth(nil)},
+			out:    [2][]int{{1, 3, 4, 5, 6}, {2}},
+			primed: 3,
+			depths: [2][]int{
+				{1, 0, 1, 0, 1, 0, 1, 0, 1, 0},
+				{1, 0},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			depths := [2][]int{{}, {}}
+			opts := tc.opts
+			if tc.depths[0] != nil {
+				opts = append(opts, chans.DistributeOnDepth(func(idx, depth int) {
+					mu.Lock()
+					defer mu.Unlock()
+					depths[idx] = append(depths[idx], depth)
+				}))
+			}
+
+			// Nothing is read until every queue is full, so that
+			// the elements queued first do not depend on timing.
+			in := make(chan int)
+			primed := make(chan struct{})
+			go func() {
+				defer close(in)
+				for ele := 1; ele <= 6; ele++ {
+					in <- ele
+					if ele == tc.primed {
+						close(primed)
+					}
+				}
+			}()
+
+			outs := chans.Distribute((<-chan int)(in), 2, opts...)
+			<-primed
+			out := [2][]int{collect(t, outs[0]), collect(t, outs[1])}
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if tc.depths[0] != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				if !reflect.DeepEqual(depths, tc.depths) {
+					t.Errorf(`expected depths %+v to equal %+v`, depths, tc.depths)
+				}
+			}
+		})
+	}
+
+	t.Run("ready first", func(t *testing.T) {
+		t.Parallel()
+
+		out := collectAll(t, chans.Distribute(chans.New(1, 2, 3, 4, 5, 6), 3))
+		all := append(append(out[0], out[1]...), out[2]...)
+		sort.Ints(all)
+		if !reflect.DeepEqual(all, []int{1, 2, 3, 4, 5, 6}) {
+			t.Errorf(`expected %+v to equal %+v`, all, []int{1, 2, 3, 4, 5, 6})
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		for _, opts := range [][]chans.DistributeOpt{nil, {chans.DistributeLeastLoaded()}} {
+			opts := opts
+			closesOnCancel(t, func(ctx context.Context, in <-chan int) <-chan int {
+				return chans.MergeSlice(chans.Distribute(in, 2, append(opts, chans.WithContext(ctx))...))
+			})
+		}
+	})
+}
+
+func TestBridge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("order", func(t *testing.T) {
+		t.Parallel()
+
+		chs := chans.New(chans.New(1, 2), chans.New(3), chans.New[int](), chans.New(4, 5))
+		out := collect(t, chans.Bridge(context.Background(), chs))
+
+		if !reflect.DeepEqual(out, []int{1, 2, 3, 4, 5}) {
+			t.Errorf(`expected %+v to equal %+v`, out, []int{1, 2, 3, 4, 5})
+		}
+	})
+
+	testCases := map[string]struct {
+		opts []chans.BridgeOpt
+		// drained indicates whether producers that ignore
+		// the context should be unblocked after cancellation.
+		drained bool
+	}{
+		"abandons inputs on cancel": {},
+		"drains inputs on cancel": {
+			opts:    []chans.BridgeOpt{chans.DrainOnCancel()},
+			drained: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			chs := make(chan (<-chan int))
+			inner := make(chan int)
+			out := chans.Bridge(ctx, chs, tc.opts...)
+
+			chs <- inner
+			inner <- 1
+			if ele := <-out; ele != 1 {
+				t.Fatalf("expected 1, but received %d", ele)
+			}
+
+			cancel()
+			if rest := collect(t, out); len(rest) != 0 {
+				t.Errorf("expected no elements after cancellation, but received %+v", rest)
+			}
+
+			// The producer ignores ctx, so it can only
+			// finish if someone keeps receiving.
+			done := make(chan struct{})
+			later := make(chan int)
+			go func() {
+				defer close(done)
+				inner <- 2
+				close(inner)
+				chs <- later
+				later <- 3
+				close(later)
+				close(chs)
+			}()
+
+			select {
+			case <-done:
+				if !tc.drained {
+					t.Errorf("expected the inputs to be abandoned")
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tc.drained {
+					t.Errorf("expected the inputs to be drained")
+				}
+			}
+
+			if !tc.drained {
+				// Unblock the producer so that it does not outlive the test.
+				chans.Drain((<-chan int)(inner))
+				for ch := range chs {
+					chans.Drain(ch)
+				}
+			}
+		})
+	}
+
+	t.Run("cancellation through WithContext is ignored", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		chs := chans.New(chans.New(1, 2))
+		out := collect(t, chans.Bridge(context.Background(), chs, chans.WithContext(ctx)))
+
+		if !reflect.DeepEqual(out, []int{1, 2}) {
+			t.Errorf(`expected %+v to equal %+v`, out, []int{1, 2})
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package chans
+
+import (
+	"time"
+)
+
+// Pipeline wraps a channel so that a multi-stage pipeline can be
+// written as a chain of method calls rather than nested function calls.
+// Every stage delegates to the package level function of the same name,
+// passing along the ChanOpts the Pipeline was created with.
+//
+// Only operations that preserve the element type can be methods.
+// Operations that change it are provided as Pipe-prefixed functions,
+// such as PipeMap and PipeBuffer.
+type Pipeline[T any] struct {
+	ch   <-chan T
+	opts []ChanOpt
+}
+
+// Pipe creates a new Pipeline reading from ch.
+// opts are applied to every stage of the pipeline.
+func Pipe[T any](ch <-chan T, opts ...ChanOpt) Pipeline[T] {
+	return Pipeline[T]{
+		ch:   ch,
+		opts: opts,
+	}
+}
+
+// Chan returns the channel produced by the final stage of p.
+func (p Pipeline[T]) Chan() <-chan T {
+	return p.ch
+}
+
+/* Stages */
+
+func (p Pipeline[T]) Append(ele T) Pipeline[T] {
+	return p.then(Append(p.ch, ele, p.opts...))
+}
+
+func (p Pipeline[T]) Debounce(d time.Duration) Pipeline[T] {
+	return p.then(Debounce(p.ch, d, p.opts...))
+}
+
+func (p Pipeline[T]) Drop(num int) Pipeline[T] {
+	return p.then(Drop(p.ch, num, p.opts...))
+}
+
+func (p Pipeline[T]) DropWhile(fn func(T) bool) Pipeline[T] {
+	return p.then(DropWhile(p.ch, fn, p.opts...))
+}
+
+func (p Pipeline[T]) Filter(fn func(T) bool) Pipeline[T] {
+	return p.then(Filter(p.ch, fn, p.opts...))
+}
+
+func (p Pipeline[T]) FilterConcurrent(fn func(T) bool, workers int) Pipeline[T] {
+	return p.then(FilterConcurrent(p.ch, fn, workers, p.opts...))
+}
+
+func (p Pipeline[T]) Latest() Pipeline[T] {
+	return p.then(Latest(p.ch, p.opts...))
+}
+
+func (p Pipeline[T]) Prepend(ele T) Pipeline[T] {
+	return p.then(Prepend(p.ch, ele, p.opts...))
+}
+
+func (p Pipeline[T]) SampleEvery(n int) Pipeline[T] {
+	return p.then(SampleEvery(p.ch, n, p.opts...))
+}
+
+func (p Pipeline[T]) SkipFor(d time.Duration) Pipeline[T] {
+	return p.then(SkipFor(p.ch, d, p.opts...))
+}
+
+func (p Pipeline[T]) SkipUntil(signal <-chan struct{}) Pipeline[T] {
+	return p.then(SkipUntil(p.ch, signal, p.opts...))
+}
+
+func (p Pipeline[T]) Take(num int) Pipeline[T] {
+	return p.then(Take(p.ch, num, p.opts...))
+}
+
+func (p Pipeline[T]) TakeFor(d time.Duration) Pipeline[T] {
+	return p.then(TakeFor(p.ch, d, p.opts...))
+}
+
+func (p Pipeline[T]) TakeUntil(signal <-chan struct{}) Pipeline[T] {
+	return p.then(TakeUntil(p.ch, signal, p.opts...))
+}
+
+func (p Pipeline[T]) TakeWhile(fn func(T) bool) Pipeline[T] {
+	return p.then(TakeWhile(p.ch, fn, p.opts...))
+}
+
+func (p Pipeline[T]) Timeout(d time.Duration) Pipeline[T] {
+	return p.then(Timeout(p.ch, d, p.opts...))
+}
+
+/* Terminals */
+
+// Collect receives every element produced by p into a slice,
+// returning once p closes. If the context p was created with is
+// cancelled first, it returns the elements received so far
+// along with the context's error.
+func (p Pipeline[T]) Collect() ([]T, error) {
+	return Collect(newChanArgs(p.opts).ctx, p.ch)
+}
+
+func (p Pipeline[T]) Drain() int {
+	return Drain(p.ch)
+}
+
+func (p Pipeline[T]) ForEach(fn func(T)) {
+	ForEach(p.ch, fn)
+}
+
+/* Type-changing stages */
+
+// PipeBuffer appends a Buffer stage to p.
+func PipeBuffer[T any](p Pipeline[T], size int) Pipeline[[]T] {
+	return Pipe(Buffer(p.ch, size, p.opts...), p.opts...)
+}
+
+// PipeFlatMap appends a FlatMap stage to p.
+func PipeFlatMap[T, U any](p Pipeline[T], fn func(T) <-chan U) Pipeline[U] {
+	return Pipe(FlatMap(p.ch, fn, p.opts...), p.opts...)
+}
+
+// PipeMap appends a Map stage to p.
+func PipeMap[T, U any](p Pipeline[T], fn func(T) U) Pipeline[U] {
+	return Pipe(Map(p.ch, fn, p.opts...), p.opts...)
+}
+
+// PipeMapConcurrent appends a MapConcurrent stage to p.
+func PipeMapConcurrent[T, U any](p Pipeline[T], fn func(T) U, workers int) Pipeline[U] {
+	return Pipe(MapConcurrent(p.ch, fn, workers, p.opts...), p.opts...)
+}
+
+// PipeWindow appends a Window stage to p.
+func PipeWindow[T any](p Pipeline[T], size int) Pipeline[[]T] {
+	return Pipe(Window(p.ch, size, p.opts...), p.opts...)
+}
+
+/* Helpers */
+
+// then creates a new Pipeline reading from ch
+// that shares the ChanOpts of p.
+func (p Pipeline[T]) then(ch <-chan T) Pipeline[T] {
+	return Pipeline[T]{
+		ch:   ch,
+		opts: p.opts,
+	}
+}
@@ -0,0 +1,80 @@
+package chans_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mcmathja/funky/chans"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fn  func(p chans.Pipeline[int]) chans.Pipeline[string]
+		out []string
+	}{
+		"chained stages": {
+			fn: func(p chans.Pipeline[int]) chans.Pipeline[string] {
+				p = p.Filter(func(i int) bool { return i%2 == 0 }).Drop(1).Take(2)
+				return chans.PipeMap(p, strconv.Itoa)
+			},
+			out: []string{"4", "6"},
+		},
+		"prepend and append": {
+			fn: func(p chans.Pipeline[int]) chans.Pipeline[string] {
+				return chans.PipeMap(p.Take(1).Prepend(0).Append(9), strconv.Itoa)
+			},
+			out: []string{"0", "1", "9"},
+		},
+		"type-changing stages": {
+			fn: func(p chans.Pipeline[int]) chans.Pipeline[string] {
+				buffers := chans.PipeBuffer(p.TakeWhile(func(i int) bool { return i < 6 }), 2)
+				return chans.PipeMap(buffers, func(b []int) string { return strconv.Itoa(len(b)) })
+			},
+			out: []string{"2", "2", "1"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p := chans.Pipe(chans.New(1, 2, 3, 4, 5, 6, 7, 8))
+			out, err := tc.fn(p).Collect()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestPipelineCollectUsesContext(t *testing.T) {
+	t.Parallel()
+
+	srcCtx, srcCancel := context.WithCancel(context.Background())
+	defer srcCancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	out, err := chans.Pipe(forever(srcCtx, 1), chans.WithContext(ctx)).
+		Filter(func(int) bool { return false }).
+		Collect()
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v to be %v", err, context.DeadlineExceeded)
+	}
+	if len(out) != 0 {
+		t.Errorf("expected no elements, but received %+v", out)
+	}
+}
@@ -0,0 +1,181 @@
+package chans
+
+import (
+	"sync"
+)
+
+// Topic is a publish-subscribe hub that delivers every published
+// element to each of a dynamic set of subscribers.
+// A Topic must be created with NewTopic and is safe for concurrent use.
+type Topic[T any] struct {
+	mu     sync.Mutex
+	subs   map[<-chan T]*subscription[T]
+	closed bool
+}
+
+// subscription is a single subscriber to a Topic.
+type subscription[T any] struct {
+	// mu is held while delivering to ch, so that
+	// ch is never closed during a delivery.
+	mu       sync.Mutex
+	ch       chan T
+	strategy OverflowStrategy
+	// done is closed to abandon any blocked delivery.
+	done   chan struct{}
+	closed bool
+}
+
+// subscribeArgs represent optional arguments to Subscribe.
+type subscribeArgs struct {
+	// capacity is the buffer size of the subscription.
+	capacity int
+	// strategy governs publishing to a full subscription.
+	strategy OverflowStrategy
+}
+
+// SubscribeOpt represent optional arguments to Subscribe.
+type SubscribeOpt func(*subscribeArgs)
+
+// SubscribeBuffer is a SubscribeOpt that gives the subscription
+// a buffer of size n, using strategy to decide what happens
+// when an element is published while the buffer is full.
+// By default, subscriptions are unbuffered and use OverflowBlock.
+func SubscribeBuffer(n int, strategy OverflowStrategy) SubscribeOpt {
+	return func(args *subscribeArgs) {
+		if n < 0 {
+			n = 0
+		}
+		args.capacity = n
+		args.strategy = strategy
+	}
+}
+
+// NewTopic creates a new Topic with no subscribers.
+func NewTopic[T any]() *Topic[T] {
+	return &Topic[T]{
+		subs: make(map[<-chan T]*subscription[T]),
+	}
+}
+
+// Close unsubscribes every subscriber, closing their channels.
+// Any later subscriptions are closed immediately
+// and any later publications are discarded.
+func (t *Topic[T]) Close() {
+	t.mu.Lock()
+	subs := t.subs
+	t.subs = make(map[<-chan T]*subscription[T])
+	t.closed = true
+	t.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.close()
+	}
+}
+
+// Publish delivers ele to every current subscriber in turn,
+// according to the overflow strategy of each subscription.
+// With OverflowBlock, Publish waits until the subscriber
+// receives ele or unsubscribes.
+func (t *Topic[T]) Publish(ele T) {
+	t.mu.Lock()
+	subs := make([]*subscription[T], 0, len(t.subs))
+	for _, sub := range t.subs {
+		subs = append(subs, sub)
+	}
+	t.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.deliver(ele)
+	}
+}
+
+// Subscribe registers a new subscriber, returning the channel
+// on which it will receive every subsequently published element.
+func (t *Topic[T]) Subscribe(opts ...SubscribeOpt) <-chan T {
+	args := subscribeArgs{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	sub := &subscription[T]{
+		ch:       make(chan T, args.capacity),
+		strategy: args.strategy,
+		done:     make(chan struct{}),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		sub.close()
+	} else {
+		t.subs[sub.ch] = sub
+	}
+
+	return sub.ch
+}
+
+// Unsubscribe removes the subscriber receiving on ch and closes ch.
+// It has no effect if ch is not subscribed to t.
+func (t *Topic[T]) Unsubscribe(ch <-chan T) {
+	t.mu.Lock()
+	sub, ok := t.subs[ch]
+	delete(t.subs, ch)
+	t.mu.Unlock()
+
+	if ok {
+		sub.close()
+	}
+}
+
+// close abandons any pending delivery and closes the subscription.
+func (s *subscription[T]) close() {
+	close(s.done)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	close(s.ch)
+}
+
+// deliver offers ele to the subscription according to its strategy.
+func (s *subscription[T]) deliver(ele T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	switch s.strategy {
+	case OverflowDropNewest:
+		select {
+		case s.ch <- ele:
+		default:
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.ch <- ele:
+				return
+			default:
+			}
+
+			// Make room by discarding the oldest element,
+			// unless the subscriber has just done so itself.
+			select {
+			case <-s.ch:
+			default:
+				if cap(s.ch) == 0 {
+					return
+				}
+			}
+		}
+	default:
+		select {
+		case s.ch <- ele:
+		case <-s.done:
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package maps
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/mcmathja/funky/constraints"
+)
+
+// MarshalJSONSorted returns the JSON encoding of m as an object
+// whose keys appear in ascending order of K, rather than in the
+// lexical order of their encoded form that encoding/json uses.
+// Keys and values are otherwise encoded exactly as json.Marshal would.
+func MarshalJSONSorted[K constraints.Ordered, V any](m map[K]V) ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range SortedKeys(m) {
+		// Encoding each entry as a single-entry map
+		// reuses encoding/json's rules for encoding keys.
+		entry, err := json.Marshal(map[K]V{k: m[k]})
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(entry[1 : len(entry)-1])
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
@@ -3,6 +3,8 @@ package maps
 
 import (
 	"errors"
+	"math/rand"
+	"sort"
 
 	"github.com/mcmathja/funky/constraints"
 	"github.com/mcmathja/funky/pairs"
@@ -10,25 +12,140 @@ import (
 
 /* Constructors */
 
-func FromBatch[K comparable, V any](g func(func(pairs.Pair[K, V]))) map[K]V {
+// fromArgs represent optional arguments to FromBatch, FromChan and FromSlice.
+type fromArgs struct {
+	// keepFirst indicates whether the first value
+	// for a repeated key should be kept.
+	keepFirst bool
+}
+
+// FromOpt represent optional arguments to FromBatch, FromChan and FromSlice.
+type FromOpt func(*fromArgs)
+
+// FromKeepFirst is a FromOpt that keeps the first value for a repeated key,
+// rather than the last. To detect repeated keys, use FromSliceStrict and
+// its counterparts instead, or FromSliceMulti and its counterparts
+// to keep every value.
+func FromKeepFirst(o *fromArgs) {
+	o.keepFirst = true
+}
+
+// FromBatch creates a new map containing all the key value pairs produced by b.
+// If the same key is repeated twice, the last value wins, unless FromKeepFirst is given.
+func FromBatch[K comparable, V any](b func(func(pairs.Pair[K, V])), opts ...FromOpt) map[K]V {
+	args := newFromArgs(opts)
+	result := make(map[K]V)
+	b(func(kv pairs.Pair[K, V]) {
+		insertPair(result, kv, args)
+	})
+
+	return result
+}
+
+// FromBatchStrict is like FromBatch, but returns an error
+// if the same key is repeated twice. Because b cannot be
+// stopped early, it still runs to completion.
+func FromBatchStrict[K comparable, V any](b func(func(pairs.Pair[K, V]))) (map[K]V, error) {
 	result := make(map[K]V)
-	g(func(pair pairs.Pair[K, V]) {
-		result[pair.Left] = pair.Right
+	var err error
+	b(func(kv pairs.Pair[K, V]) {
+		if err == nil {
+			err = insertStrict(result, kv)
+		}
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FromChan creates a new map containing all the key value pairs received on ch.
+// It only returns its results once the channel closes. If the same key is
+// repeated twice, the last value wins, unless FromKeepFirst is given.
+func FromChan[K comparable, V any](ch <-chan pairs.Pair[K, V], opts ...FromOpt) map[K]V {
+	args := newFromArgs(opts)
+	result := make(map[K]V)
+	for kv := range ch {
+		insertPair(result, kv, args)
+	}
+
 	return result
 }
 
-// FromChannel creates a new map containing all the distinct values received on ch.
-// It only returns its results once the channel closes.
-func FromChan[K comparable, V any](ch <-chan pairs.Pair[K, V]) map[K]V {
+// FromChanStrict is like FromChan, but returns an error if the same key
+// is repeated twice. It stops receiving from ch at the repeated key,
+// so the producer must not rely on ch being drained.
+func FromChanStrict[K comparable, V any](ch <-chan pairs.Pair[K, V]) (map[K]V, error) {
 	result := make(map[K]V)
 	for kv := range ch {
-		result[kv.Left] = kv.Right
+		if err := insertStrict(result, kv); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// FromKeys creates a new map from each of the provided keys
+// to the result of applying fn to that key.
+func FromKeys[K comparable, V any](keys []K, fn func(K) V) map[K]V {
+	result := make(map[K]V, len(keys))
+	for _, k := range keys {
+		result[k] = fn(k)
 	}
 
 	return result
 }
 
+// fromKeysAndValuesArgs represent optional arguments to FromKeysAndValues.
+type fromKeysAndValuesArgs[V any] struct {
+	// fill is the value for keys without a corresponding value.
+	fill V
+	// pad indicates whether keys without a corresponding value
+	// should map to fill rather than causing an error.
+	pad bool
+}
+
+// FromKeysAndValuesOpt represent optional arguments to FromKeysAndValues.
+type FromKeysAndValuesOpt[V any] func(*fromKeysAndValuesArgs[V])
+
+// FromKeysAndValuesWithFill is a FromKeysAndValuesOpt that makes
+// any keys beyond the end of values map to v instead of causing an error.
+func FromKeysAndValuesWithFill[V any](v V) FromKeysAndValuesOpt[V] {
+	return func(o *fromKeysAndValuesArgs[V]) {
+		o.fill = v
+		o.pad = true
+	}
+}
+
+// FromKeysAndValues creates a new map from each key in keys to the value
+// at the same index in values. It returns an error if there are more
+// values than keys, or more keys than values unless
+// FromKeysAndValuesWithFill is given. If the same key is repeated twice,
+// the last value wins.
+func FromKeysAndValues[K comparable, V any](keys []K, values []V, opts ...FromKeysAndValuesOpt[V]) (map[K]V, error) {
+	args := fromKeysAndValuesArgs[V]{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	if len(values) > len(keys) || (len(values) < len(keys) && !args.pad) {
+		return nil, errors.New("length mismatch")
+	}
+
+	result := make(map[K]V, len(keys))
+	for idx, k := range keys {
+		if idx < len(values) {
+			result[k] = values[idx]
+		} else {
+			result[k] = args.fill
+		}
+	}
+
+	return result, nil
+}
+
 // FromSet creates a new map containing all the key value pairs in s.
 // If the same key is repeated twice, a value is chosen arbitrarily.
 func FromSet[K comparable, V comparable](s map[pairs.Pair[K, V]]struct{}) map[K]V {
@@ -41,16 +158,30 @@ func FromSet[K comparable, V comparable](s map[pairs.Pair[K, V]]struct{}) map[K]
 }
 
 // FromSlice creates a new map containing all the key value pairs in s.
-// If the same key is repeated twice, the last value wins.
-func FromSlice[K comparable, V any](s []pairs.Pair[K, V]) map[K]V {
+// If the same key is repeated twice, the last value wins, unless FromKeepFirst is given.
+func FromSlice[K comparable, V any](s []pairs.Pair[K, V], opts ...FromOpt) map[K]V {
+	args := newFromArgs(opts)
 	result := make(map[K]V)
 	for _, kv := range s {
-		result[kv.Left] = kv.Right
+		insertPair(result, kv, args)
 	}
 
 	return result
 }
 
+// FromSliceStrict is like FromSlice, but returns
+// an error if the same key is repeated twice.
+func FromSliceStrict[K comparable, V any](s []pairs.Pair[K, V]) (map[K]V, error) {
+	result := make(map[K]V, len(s))
+	for _, kv := range s {
+		if err := insertStrict(result, kv); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
 func New[K comparable, V any](kvs ...pairs.Pair[K, V]) map[K]V {
 	result := make(map[K]V, len(kvs))
 	for _, kv := range kvs {
@@ -92,6 +223,49 @@ func Any[K comparable, V any](m map[K]V, fn func(K, V) bool) bool {
 	return false
 }
 
+// ContainsAllKeys checks if m contains every key in keys.
+// Use MissingKeys to find out which keys are absent.
+func ContainsAllKeys[K comparable, V any](m map[K]V, keys ...K) bool {
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ContainsAllValues checks if m contains every value in vs.
+func ContainsAllValues[K comparable, V comparable](m map[K]V, vs ...V) bool {
+	if len(vs) == 0 {
+		return true
+	}
+
+	missing := make(map[V]struct{})
+	for _, v := range vs {
+		missing[v] = struct{}{}
+	}
+	for _, v := range m {
+		delete(missing, v)
+		if len(missing) == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContainsAnyKeys checks if m contains any key in keys.
+func ContainsAnyKeys[K comparable, V any](m map[K]V, keys ...K) bool {
+	for _, k := range keys {
+		if _, ok := m[k]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 func ContainsKey[K comparable, V any](m map[K]V, k K) bool {
 	_, ok := m[k]
 	return ok
@@ -119,6 +293,51 @@ func Count[K comparable, V comparable](m map[K]V, v V, fn func(K, V) bool) int {
 	return cnt
 }
 
+// CountBy produces a map from the distinct results of fn,
+// applied against each key value pair in m,
+// to the number of occurrences of that result.
+func CountBy[K comparable, V any, U comparable](m map[K]V, fn func(K, V) U) map[U]int {
+	cnts := make(map[U]int)
+	for k, v := range m {
+		cnts[fn(k, v)]++
+	}
+
+	return cnts
+}
+
+// Diff compares before and after, returning the key value pairs in after
+// whose keys are not in before, the key value pairs in before whose keys
+// are not in after, and the key value pairs in after whose keys are in
+// before with a different value. Use DiffBy if V is not comparable.
+func Diff[K, V comparable](before, after map[K]V) (added, removed, changed map[K]V) {
+	return DiffBy(before, after, func(a, b V) bool {
+		return a == b
+	})
+}
+
+// DiffBy is like Diff, but determines whether
+// a value has changed using the equality function eq.
+func DiffBy[K comparable, V any](before, after map[K]V, eq func(a, b V) bool) (added, removed, changed map[K]V) {
+	added = make(map[K]V)
+	removed = make(map[K]V)
+	changed = make(map[K]V)
+
+	for k, v := range after {
+		if prev, ok := before[k]; !ok {
+			added[k] = v
+		} else if !eq(prev, v) {
+			changed[k] = v
+		}
+	}
+	for k, v := range before {
+		if _, ok := after[k]; !ok {
+			removed[k] = v
+		}
+	}
+
+	return added, removed, changed
+}
+
 func Drop[K comparable, V any](m map[K]V, num int) map[K]V {
 	result := make(map[K]V)
 
@@ -133,6 +352,20 @@ func Drop[K comparable, V any](m map[K]V, num int) map[K]V {
 	return result
 }
 
+// DropSorted creates a new map containing the key value pairs in m
+// except for those with the num smallest keys. Unlike Drop,
+// its result does not depend on the iteration order of m.
+func DropSorted[K constraints.Ordered, V any](m map[K]V, num int) map[K]V {
+	result := make(map[K]V)
+	for i, k := range SortedKeys(m) {
+		if i >= num {
+			result[k] = m[k]
+		}
+	}
+
+	return result
+}
+
 func DropWhile[K comparable, V any](m map[K]V, fn func(K, V) bool) map[K]V {
 	result := make(map[K]V)
 	done := false
@@ -149,10 +382,51 @@ func DropWhile[K comparable, V any](m map[K]V, fn func(K, V) bool) map[K]V {
 	return result
 }
 
+// DropWhileSorted creates a new map containing the key value pairs in m
+// except for those visited in ascending key order before the first
+// one that does not satisfy fn. Unlike DropWhile,
+// its result does not depend on the iteration order of m.
+func DropWhileSorted[K constraints.Ordered, V any](m map[K]V, fn func(K, V) bool) map[K]V {
+	result := make(map[K]V)
+	done := false
+	for _, k := range SortedKeys(m) {
+		if !done && !fn(k, m[k]) {
+			done = true
+		}
+		if done {
+			result[k] = m[k]
+		}
+	}
+
+	return result
+}
+
 func Empty[K comparable, V any](m map[K]V) bool {
 	return len(m) == 0
 }
 
+// Entries returns the key value pairs in m, in an arbitrary order.
+// Use EntriesSorted for ascending key order,
+// or SortedPairs for any other order.
+func Entries[K comparable, V any](m map[K]V) []pairs.Pair[K, V] {
+	result := make([]pairs.Pair[K, V], 0, len(m))
+	for k, v := range m {
+		result = append(result, pairs.New(k, v))
+	}
+
+	return result
+}
+
+// EntriesSorted returns the key value pairs in m in ascending key order.
+func EntriesSorted[K constraints.Ordered, V any](m map[K]V) []pairs.Pair[K, V] {
+	result := make([]pairs.Pair[K, V], 0, len(m))
+	for _, k := range SortedKeys(m) {
+		result = append(result, pairs.New(k, m[k]))
+	}
+
+	return result
+}
+
 func Equals[K comparable, V comparable](a, b map[K]V) bool {
 	if len(a) != len(b) {
 		return false
@@ -178,6 +452,34 @@ func Filter[K comparable, V any](m map[K]V, fn func(K, V) bool) map[K]V {
 	return result
 }
 
+// Find returns a key value pair in m that satisfies fn,
+// or false if there is none. If several pairs satisfy fn,
+// which one is returned is arbitrary.
+func Find[K comparable, V any](m map[K]V, fn func(K, V) bool) (K, V, bool) {
+	for k, v := range m {
+		if fn(k, v) {
+			return k, v, true
+		}
+	}
+
+	var k K
+	var v V
+	return k, v, false
+}
+
+// FindKey returns a key in m that maps to v, or false if there is none.
+// If several keys map to v, which one is returned is arbitrary.
+func FindKey[K comparable, V comparable](m map[K]V, v V) (K, bool) {
+	for k, cur := range m {
+		if cur == v {
+			return k, true
+		}
+	}
+
+	var k K
+	return k, false
+}
+
 func FlatMap[K, T comparable, V, U any](m map[K]V, fn func(K, V) map[T]U) map[T]U {
 	result := make(map[T]U)
 	for k, v := range m {
@@ -189,6 +491,18 @@ func FlatMap[K, T comparable, V, U any](m map[K]V, fn func(K, V) map[T]U) map[T]
 	return result
 }
 
+// FlatMapToSlice returns the concatenation of the slices
+// produced by applying fn to each key value pair in m.
+// Entries are visited in an arbitrary order.
+func FlatMapToSlice[K comparable, V any, T any](m map[K]V, fn func(K, V) []T) []T {
+	result := make([]T, 0, len(m))
+	for k, v := range m {
+		result = append(result, fn(k, v)...)
+	}
+
+	return result
+}
+
 func Flatten[K comparable, V any](mm []map[K]V) map[K]V {
 	result := make(map[K]V)
 	for _, m := range mm {
@@ -206,8 +520,113 @@ func ForEach[K comparable, V any](m map[K]V, fn func(key K, value V)) {
 	}
 }
 
+// ForEachSorted calls fn on each key value pair in m in ascending order of key.
+func ForEachSorted[K constraints.Ordered, V any](m map[K]V, fn func(key K, value V)) {
+	for _, k := range SortedKeys(m) {
+		fn(k, m[k])
+	}
+}
+
+// GetOr returns the value for k in m, or fallback if k is not present.
+func GetOr[K comparable, V any](m map[K]V, k K, fallback V) V {
+	if v, ok := m[k]; ok {
+		return v
+	}
+
+	return fallback
+}
+
+// GetOrElse returns the value for k in m, or the result
+// of calling fn if k is not present. fn is only called
+// when its result is needed.
+func GetOrElse[K comparable, V any](m map[K]V, k K, fn func() V) V {
+	if v, ok := m[k]; ok {
+		return v
+	}
+
+	return fn()
+}
+
+// GetOrInsert returns the value for k in m along with m itself
+// if k is present. Otherwise, it returns the result of calling fn
+// along with a copy of m in which k maps to that result.
+// m is never modified.
+func GetOrInsert[K comparable, V any](m map[K]V, k K, fn func() V) (map[K]V, V) {
+	if v, ok := m[k]; ok {
+		return m, v
+	}
+
+	v := fn()
+	return Add(m, k, v), v
+}
+
+// GroupBy splits m into groups keyed by the result of fn,
+// applied against each key value pair in m.
+// Use InvertGroup to group the keys of m by value.
+func GroupBy[K comparable, V any, G comparable](m map[K]V, fn func(K, V) G) map[G]map[K]V {
+	result := make(map[G]map[K]V)
+	for k, v := range m {
+		g := fn(k, v)
+		if result[g] == nil {
+			result[g] = make(map[K]V)
+		}
+		result[g][k] = v
+	}
+
+	return result
+}
+
+// invertArgs represent optional arguments to Invert.
+type invertArgs struct {
+	// strict indicates whether duplicate values are an error.
+	strict bool
+}
+
+// InvertOpt represent optional arguments to Invert.
+type InvertOpt func(*invertArgs)
+
+// InvertStrict is an InvertOpt that makes Invert return
+// an error if more than one key maps to the same value.
+func InvertStrict(o *invertArgs) {
+	o.strict = true
+}
+
+// Invert creates a new map from each value in m to its key.
+// If more than one key maps to the same value, one of them is
+// chosen arbitrarily, unless InvertStrict is given, in which case
+// an error is returned. Use InvertGroup to keep every key.
+func Invert[K, V comparable](m map[K]V, opts ...InvertOpt) (map[V]K, error) {
+	args := invertArgs{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	result := make(map[V]K, len(m))
+	for k, v := range m {
+		if _, ok := result[v]; ok && args.strict {
+			return nil, errors.New("duplicate value")
+		}
+		result[v] = k
+	}
+
+	return result, nil
+}
+
+// InvertGroup creates a new map from each value in m
+// to all of the keys that map to it, in an arbitrary order.
+func InvertGroup[K, V comparable](m map[K]V) map[V][]K {
+	result := make(map[V][]K)
+	for k, v := range m {
+		result[v] = append(result[v], k)
+	}
+
+	return result
+}
+
+// Keys returns the keys in m, in an arbitrary order.
+// Use SortedKeys for a deterministic order.
 func Keys[K comparable, V any](m map[K]V) []K {
-	result := make([]K, len(m))
+	result := make([]K, 0, len(m))
 	for k := range m {
 		result = append(result, k)
 	}
@@ -215,6 +634,19 @@ func Keys[K comparable, V any](m map[K]V) []K {
 	return result
 }
 
+// KeysWhere returns the keys of the key value pairs
+// in m that satisfy the predicate fn, in an arbitrary order.
+func KeysWhere[K comparable, V any](m map[K]V, fn func(K, V) bool) []K {
+	result := make([]K, 0)
+	for k, v := range m {
+		if fn(k, v) {
+			result = append(result, k)
+		}
+	}
+
+	return result
+}
+
 func Map[K1, K2 comparable, V1, V2 any](m map[K1]V1, fn func(K1, V1) (K2, V2)) map[K2]V2 {
 	result := make(map[K2]V2, len(m))
 	ForEach(m, func(k1 K1, v1 V1) {
@@ -305,6 +737,39 @@ func MinValue[K comparable, V constraints.Ordered](m map[K]V) (V, error) {
 	return best, nil
 }
 
+// MissingKeys returns the keys in keys that are not present in m,
+// in the order they are provided. Repeated keys are only returned once.
+func MissingKeys[K comparable, V any](m map[K]V, keys ...K) []K {
+	result := make([]K, 0)
+	seen := make(map[K]struct{})
+	for _, k := range keys {
+		if _, ok := m[k]; ok {
+			continue
+		}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		result = append(result, k)
+	}
+
+	return result
+}
+
+// Omit creates a new map containing the key value pairs
+// in m except for those with any of the provided keys.
+func Omit[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	result := make(map[K]V, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	for _, k := range keys {
+		delete(result, k)
+	}
+
+	return result
+}
+
 func Partition[K comparable, V any](m map[K]V, fn func(K, V) bool) (map[K]V, map[K]V) {
 	a := make(map[K]V)
 	b := make(map[K]V)
@@ -320,6 +785,19 @@ func Partition[K comparable, V any](m map[K]V, fn func(K, V) bool) (map[K]V, map
 	return a, b
 }
 
+// Pick creates a new map containing only the key value pairs
+// in m with the provided keys. Keys not present in m are ignored.
+func Pick[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	result := make(map[K]V, len(keys))
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
 func ProductKeys[K constraints.Numeric, V any](m map[K]V) K {
 	var product K
 	for k := range m {
@@ -347,6 +825,35 @@ func Reduce[K comparable, V any, U any](m map[K]V, initial U, fn func(U, K, V) U
 	return *acc
 }
 
+// ReduceSorted reduces m to a single value by applying fn
+// to each key value pair in ascending key order. Unlike Reduce,
+// its result does not depend on the iteration order of m,
+// so fn need not be commutative.
+func ReduceSorted[K constraints.Ordered, V any, U any](m map[K]V, initial U, fn func(U, K, V) U) U {
+	acc := initial
+	for _, k := range SortedKeys(m) {
+		acc = fn(acc, k, m[k])
+	}
+
+	return acc
+}
+
+// ReduceWhile reduces m to a single value by applying fn to each
+// key value pair, in an arbitrary order, until fn returns false.
+// The value fn returns along with false is the result.
+func ReduceWhile[K comparable, V any, U any](m map[K]V, initial U, fn func(U, K, V) (U, bool)) U {
+	acc := initial
+	for k, v := range m {
+		var ok bool
+		acc, ok = fn(acc, k, v)
+		if !ok {
+			break
+		}
+	}
+
+	return acc
+}
+
 func Remove[K comparable, V any](m map[K]V, k K) map[K]V {
 	result := make(map[K]V, len(m))
 	for key, value := range m {
@@ -357,10 +864,82 @@ func Remove[K comparable, V any](m map[K]V, k K) map[K]V {
 	return result
 }
 
+// randomArgs represent optional arguments to Sample and TakeRandom.
+type randomArgs struct {
+	// random returns a uniformly distributed integer in the range [0, n).
+	random func(n int) int
+}
+
+// RandomOpt represent optional arguments to Sample and TakeRandom.
+type RandomOpt func(*randomArgs)
+
+// WithRandom is a RandomOpt that makes random selections draw from fn,
+// which must return a uniformly distributed integer in the range [0, n).
+// By default, math/rand.Intn is used.
+func WithRandom(fn func(n int) int) RandomOpt {
+	return func(o *randomArgs) {
+		o.random = fn
+	}
+}
+
+// Sample returns a key value pair chosen uniformly at random from m.
+// If m is empty, or the function given through WithRandom returns
+// an index outside of m, it returns an error.
+// Unlike ranging over m and stopping early, every entry is equally likely.
+func Sample[K comparable, V any](m map[K]V, opts ...RandomOpt) (K, V, error) {
+	var k K
+	var v V
+	if len(m) <= 0 {
+		return k, v, errors.New("no such element")
+	}
+
+	args := newRandomArgs(opts)
+	idx := args.random(len(m))
+	if idx < 0 || idx >= len(m) {
+		return k, v, errors.New("random index out of range")
+	}
+
+	for k, v = range m {
+		if idx == 0 {
+			break
+		}
+		idx--
+	}
+
+	return k, v, nil
+}
+
 func Size[K comparable, V any](m map[K]V) int {
 	return len(m)
 }
 
+// SortedKeys returns the keys in m in ascending order.
+func SortedKeys[K constraints.Ordered, V any](m map[K]V) []K {
+	result := make([]K, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result
+}
+
+// SortedPairs returns the key value pairs in m
+// in the order determined by less.
+func SortedPairs[K comparable, V any](m map[K]V, less func(a, b pairs.Pair[K, V]) bool) []pairs.Pair[K, V] {
+	result := make([]pairs.Pair[K, V], 0, len(m))
+	for k, v := range m {
+		result = append(result, pairs.New(k, v))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return less(result[i], result[j])
+	})
+
+	return result
+}
+
 func SumKeys[K constraints.Numeric, V any](m map[K]V) K {
 	var sum K
 	for k := range m {
@@ -377,6 +956,14 @@ func SumValues[K comparable, V constraints.Numeric](m map[K]V) V {
 	return sum
 }
 
+// TallyValues produces a map from each distinct value in m
+// to the number of keys that map to that value.
+func TallyValues[K, V comparable](m map[K]V) map[V]int {
+	return CountBy(m, func(_ K, v V) V {
+		return v
+	})
+}
+
 func Take[K comparable, V any](m map[K]V, num int) map[K]V {
 	if num <= 0 {
 		return map[K]V{}
@@ -395,6 +982,41 @@ func Take[K comparable, V any](m map[K]V, num int) map[K]V {
 	return result
 }
 
+// TakeRandom returns a map containing num key value pairs chosen
+// uniformly at random from m, without replacement.
+// If num is greater than the size of m, it contains all of them.
+func TakeRandom[K comparable, V any](m map[K]V, num int, opts ...RandomOpt) map[K]V {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	args := newRandomArgs(opts)
+	result := make(map[K]V)
+	for i := 0; i < num && i < len(keys); i++ {
+		j := i + args.random(len(keys)-i)
+		keys[i], keys[j] = keys[j], keys[i]
+		result[keys[i]] = m[keys[i]]
+	}
+
+	return result
+}
+
+// TakeSorted creates a new map containing the key value pairs
+// in m with the num smallest keys. Unlike Take,
+// its result does not depend on the iteration order of m.
+func TakeSorted[K constraints.Ordered, V any](m map[K]V, num int) map[K]V {
+	result := make(map[K]V)
+	for i, k := range SortedKeys(m) {
+		if i >= num {
+			break
+		}
+		result[k] = m[k]
+	}
+
+	return result
+}
+
 func TakeWhile[K comparable, V any](m map[K]V, fn func(K, V) bool) map[K]V {
 	result := make(map[K]V)
 
@@ -408,11 +1030,181 @@ func TakeWhile[K comparable, V any](m map[K]V, fn func(K, V) bool) map[K]V {
 	return result
 }
 
+// TakeWhileSorted creates a new map containing the key value pairs
+// in m visited in ascending key order before the first one that
+// does not satisfy fn. Unlike TakeWhile,
+// its result does not depend on the iteration order of m.
+func TakeWhileSorted[K constraints.Ordered, V any](m map[K]V, fn func(K, V) bool) map[K]V {
+	result := make(map[K]V)
+	for _, k := range SortedKeys(m) {
+		if !fn(k, m[k]) {
+			break
+		}
+		result[k] = m[k]
+	}
+
+	return result
+}
+
+// ToSlice returns the result of applying fn to
+// each key value pair in m, in an arbitrary order.
+func ToSlice[K comparable, V any, T any](m map[K]V, fn func(K, V) T) []T {
+	result := make([]T, 0, len(m))
+	for k, v := range m {
+		result = append(result, fn(k, v))
+	}
+
+	return result
+}
+
+// TryFilter behaves like Filter, but for a fallible predicate.
+// It stops at the first error returned by fn and returns it
+// along with a nil map. Since entries are visited in an
+// arbitrary order, which error is returned is unspecified
+// when fn fails for more than one entry.
+func TryFilter[K comparable, V any](m map[K]V, fn func(K, V) (bool, error)) (map[K]V, error) {
+	result := make(map[K]V)
+	for k, v := range m {
+		keep, err := fn(k, v)
+		if err != nil {
+			return nil, err
+		}
+		if keep {
+			result[k] = v
+		}
+	}
+
+	return result, nil
+}
+
+// TryForEach performs fn on each key value pair in m,
+// stopping at and returning the first error encountered.
+// If fn succeeds for every entry, it returns nil.
+func TryForEach[K comparable, V any](m map[K]V, fn func(key K, value V) error) error {
+	for k, v := range m {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TryMap behaves like Map, but for a fallible mapping function.
+// It stops at the first error returned by fn and returns it
+// along with a nil map. Since entries are visited in an
+// arbitrary order, which error is returned is unspecified
+// when fn fails for more than one entry.
+func TryMap[K1, K2 comparable, V1, V2 any](m map[K1]V1, fn func(K1, V1) (K2, V2, error)) (map[K2]V2, error) {
+	result := make(map[K2]V2)
+	for k1, v1 := range m {
+		k2, v2, err := fn(k1, v1)
+		if err != nil {
+			return nil, err
+		}
+		result[k2] = v2
+	}
+
+	return result, nil
+}
+
+// UpdateAll creates a copy of m in which each of the provided keys
+// maps to the result of calling fn with that key, its value in m,
+// and whether it is present in m.
+func UpdateAll[K comparable, V any](m map[K]V, fn func(k K, old V, exists bool) V, keys ...K) map[K]V {
+	result := make(map[K]V, len(m)+len(keys))
+	for k, v := range m {
+		result[k] = v
+	}
+	for _, k := range keys {
+		old, ok := m[k]
+		result[k] = fn(k, old, ok)
+	}
+
+	return result
+}
+
+// UpdateWith creates a copy of m in which k maps to the result
+// of calling fn with its value in m and whether it is present in m.
+func UpdateWith[K comparable, V any](m map[K]V, k K, fn func(old V, exists bool) V) map[K]V {
+	old, ok := m[k]
+	return Add(m, k, fn(old, ok))
+}
+
+// Values returns the values in m, in an arbitrary order.
+// Use ValuesSortedByKey for a deterministic order.
 func Values[K comparable, V any](m map[K]V) []V {
-	result := make([]V, len(m))
+	result := make([]V, 0, len(m))
 	for _, v := range m {
 		result = append(result, v)
 	}
 
 	return result
 }
+
+// ValuesSortedByKey returns the values in m
+// in ascending order of their keys.
+func ValuesSortedByKey[K constraints.Ordered, V any](m map[K]V) []V {
+	result := make([]V, 0, len(m))
+	for _, k := range SortedKeys(m) {
+		result = append(result, m[k])
+	}
+
+	return result
+}
+
+// ValuesWhere returns the values of the key value pairs
+// in m that satisfy the predicate fn, in an arbitrary order.
+func ValuesWhere[K comparable, V any](m map[K]V, fn func(K, V) bool) []V {
+	result := make([]V, 0)
+	for k, v := range m {
+		if fn(k, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+/* Helpers */
+
+// newFromArgs applies opts over the default fromArgs.
+func newFromArgs(opts []FromOpt) fromArgs {
+	args := fromArgs{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	return args
+}
+
+// insertPair adds kv to m, resolving a repeated key as configured by args.
+func insertPair[K comparable, V any](m map[K]V, kv pairs.Pair[K, V], args fromArgs) {
+	if _, ok := m[kv.Left]; ok && args.keepFirst {
+		return
+	}
+
+	m[kv.Left] = kv.Right
+}
+
+// insertStrict adds kv to m, returning an error if its key is already present.
+func insertStrict[K comparable, V any](m map[K]V, kv pairs.Pair[K, V]) error {
+	if _, ok := m[kv.Left]; ok {
+		return errors.New("duplicate key")
+	}
+
+	m[kv.Left] = kv.Right
+	return nil
+}
+
+// newRandomArgs applies opts over the default randomArgs.
+func newRandomArgs(opts []RandomOpt) randomArgs {
+	args := randomArgs{
+		random: rand.Intn,
+	}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	return args
+}
@@ -0,0 +1,1294 @@
+package maps_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/mcmathja/funky/maps"
+	"github.com/mcmathja/funky/pairs"
+)
+
+func TestKeysAndValues(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in     map[string]int
+		keys   []string
+		values []int
+	}{
+		"simple case": {
+			in:     map[string]int{"a": 1, "b": 2, "c": 3},
+			keys:   []string{"a", "b", "c"},
+			values: []int{1, 2, 3},
+		},
+		"empty input": {
+			in:     map[string]int{},
+			keys:   []string{},
+			values: []int{},
+		},
+		"nil input": {
+			in:     nil,
+			keys:   []string{},
+			values: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			keys := maps.Keys(tc.in)
+			sort.Strings(keys)
+			values := maps.Values(tc.in)
+			sort.Ints(values)
+
+			if !reflect.DeepEqual(keys, tc.keys) {
+				t.Errorf(`expected %+v to equal %+v`, keys, tc.keys)
+			}
+			if !reflect.DeepEqual(values, tc.values) {
+				t.Errorf(`expected %+v to equal %+v`, values, tc.values)
+			}
+			if sorted := maps.SortedKeys(tc.in); !reflect.DeepEqual(sorted, tc.keys) {
+				t.Errorf(`expected %+v to equal %+v`, sorted, tc.keys)
+			}
+			if sorted := maps.ValuesSortedByKey(tc.in); !reflect.DeepEqual(sorted, tc.values) {
+				t.Errorf(`expected %+v to equal %+v`, sorted, tc.values)
+			}
+		})
+	}
+}
+
+func TestFromSliceDuplicates(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in        []pairs.Pair[string, int]
+		last      map[string]int
+		first     map[string]int
+		multi     map[string][]int
+		strictErr bool
+	}{
+		"no duplicates": {
+			in:    []pairs.Pair[string, int]{pairs.New("a", 1), pairs.New("b", 2)},
+			last:  map[string]int{"a": 1, "b": 2},
+			first: map[string]int{"a": 1, "b": 2},
+			multi: map[string][]int{"a": {1}, "b": {2}},
+		},
+		"duplicate keys": {
+			in:        []pairs.Pair[string, int]{pairs.New("a", 1), pairs.New("b", 2), pairs.New("a", 3)},
+			last:      map[string]int{"a": 3, "b": 2},
+			first:     map[string]int{"a": 1, "b": 2},
+			multi:     map[string][]int{"a": {1, 3}, "b": {2}},
+			strictErr: true,
+		},
+		"empty input": {
+			in:    []pairs.Pair[string, int]{},
+			last:  map[string]int{},
+			first: map[string]int{},
+			multi: map[string][]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out := maps.FromSlice(tc.in); !reflect.DeepEqual(out, tc.last) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.last)
+			}
+			if out := maps.FromSlice(tc.in, maps.FromKeepFirst); !reflect.DeepEqual(out, tc.first) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.first)
+			}
+			if out := maps.FromSliceMulti(tc.in); !reflect.DeepEqual(out, tc.multi) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.multi)
+			}
+
+			out, err := maps.FromSliceStrict(tc.in)
+			if tc.strictErr {
+				if err == nil || out != nil {
+					t.Errorf("expected an error, but received %+v", out)
+				}
+			} else if err != nil || !reflect.DeepEqual(out, tc.last) {
+				t.Errorf(`expected %+v to equal %+v, but received error %v`, out, tc.last, err)
+			}
+
+			b := func(next func(pairs.Pair[string, int])) {
+				for _, kv := range tc.in {
+					next(kv)
+				}
+			}
+			if out := maps.FromBatch(b); !reflect.DeepEqual(out, tc.last) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.last)
+			}
+			if out := maps.FromBatch(b, maps.FromKeepFirst); !reflect.DeepEqual(out, tc.first) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.first)
+			}
+			if out := maps.FromBatchMulti(b); !reflect.DeepEqual(out, tc.multi) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.multi)
+			}
+			if out, err := maps.FromBatchStrict(b); (err != nil) != tc.strictErr {
+				t.Errorf(`expected an error only for duplicate keys, but received %+v and error %v`, out, err)
+			}
+		})
+	}
+}
+
+func TestFromKeysAndValues(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		keys   []string
+		values []int
+		opts   []maps.FromKeysAndValuesOpt[int]
+		out    map[string]int
+		err    bool
+	}{
+		"equal lengths": {
+			keys:   []string{"a", "b"},
+			values: []int{1, 2},
+			out:    map[string]int{"a": 1, "b": 2},
+		},
+		"fewer values": {
+			keys:   []string{"a", "b"},
+			values: []int{1},
+			err:    true,
+		},
+		"more values": {
+			keys:   []string{"a"},
+			values: []int{1, 2},
+			err:    true,
+		},
+		"fewer values with fill": {
+			keys:   []string{"a", "b", "c"},
+			values: []int{1},
+			opts:   []maps.FromKeysAndValuesOpt[int]{maps.FromKeysAndValuesWithFill(9)},
+			out:    map[string]int{"a": 1, "b": 9, "c": 9},
+		},
+		"more values with fill": {
+			keys:   []string{"a"},
+			values: []int{1, 2},
+			opts:   []maps.FromKeysAndValuesOpt[int]{maps.FromKeysAndValuesWithFill(9)},
+			err:    true,
+		},
+		"empty input": {
+			keys:   []string{},
+			values: []int{},
+			out:    map[string]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := maps.FromKeysAndValues(tc.keys, tc.values, tc.opts...)
+
+			if tc.err {
+				if err == nil {
+					t.Errorf("expected an error, but received %+v", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestMemo(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		opts   []maps.MemoOpt
+		gets   []int
+		calls  int
+		cached []int
+	}{
+		"unbounded": {
+			gets:   []int{1, 2, 1, 3, 2},
+			calls:  3,
+			cached: []int{1, 2, 3},
+		},
+		"evicts least recently used": {
+			opts:   []maps.MemoOpt{maps.MemoMaxSize(2)},
+			gets:   []int{1, 2, 1, 3},
+			calls:  3,
+			cached: []int{1, 3},
+		},
+		"recomputes evicted keys": {
+			opts:   []maps.MemoOpt{maps.MemoMaxSize(2)},
+			gets:   []int{1, 2, 3, 1},
+			calls:  4,
+			cached: []int{1, 3},
+		},
+		"concurrent": {
+			opts:   []maps.MemoOpt{maps.MemoConcurrent, maps.MemoMaxSize(2)},
+			gets:   []int{1, 2, 1, 3},
+			calls:  3,
+			cached: []int{1, 3},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+			m := maps.NewMemo(func(k int) int {
+				calls++
+				return k * 10
+			}, tc.opts...)
+
+			for _, k := range tc.gets {
+				if v := m.Get(k); v != k*10 {
+					t.Errorf("expected %d, but received %d", k*10, v)
+				}
+			}
+
+			if calls != tc.calls {
+				t.Errorf("expected %d calls, but received %d", tc.calls, calls)
+			}
+			if m.Len() != len(tc.cached) {
+				t.Errorf("expected %d cached values, but received %d", len(tc.cached), m.Len())
+			}
+			for _, k := range tc.cached {
+				if v, ok := m.Peek(k); !ok || v != k*10 {
+					t.Errorf("expected %d to be cached as %d, but received %d, %t", k, k*10, v, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoPanic(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]maps.MemoOpt{
+		"sequential": nil,
+		"concurrent": {maps.MemoConcurrent},
+	} {
+		opts := opts
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			fail := true
+			m := maps.NewMemo(func(k int) int {
+				if fail {
+					panic("failed")
+				}
+				return k
+			}, opts...)
+
+			func() {
+				defer func() {
+					if r := recover(); r != "failed" {
+						t.Errorf("expected the panic to propagate, but recovered %v", r)
+					}
+				}()
+				m.Get(1)
+			}()
+
+			if m.Len() != 0 {
+				t.Errorf("expected nothing to be cached, but received %d values", m.Len())
+			}
+			if _, ok := m.Peek(1); ok {
+				t.Errorf("expected 1 not to be cached")
+			}
+
+			fail = false
+			if v := m.Get(1); v != 1 {
+				t.Errorf("expected 1, but received %d", v)
+			}
+		})
+	}
+}
+
+func TestMarshalJSONSorted(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  map[int]string
+		out string
+	}{
+		"numeric key order": {
+			in:  map[int]string{10: "a", 9: "b", 100: "c", -1: "d"},
+			out: `{"-1":"d","9":"b","10":"a","100":"c"}`,
+		},
+		"empty input": {
+			in:  map[int]string{},
+			out: `{}`,
+		},
+		"nil input": {
+			in:  nil,
+			out: `null`,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := maps.MarshalJSONSorted(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != tc.out {
+				t.Errorf("expected %s to equal %s", out, tc.out)
+			}
+		})
+	}
+}
+
+func TestInvert(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in    map[string]int
+		group map[int][]string
+		// strictErr indicates whether InvertStrict should fail.
+		strictErr bool
+	}{
+		"distinct values": {
+			in:    map[string]int{"a": 1, "b": 2},
+			group: map[int][]string{1: {"a"}, 2: {"b"}},
+		},
+		"duplicate values": {
+			in:        map[string]int{"a": 1, "b": 2, "c": 1},
+			group:     map[int][]string{1: {"a", "c"}, 2: {"b"}},
+			strictErr: true,
+		},
+		"empty input": {
+			in:    map[string]int{},
+			group: map[int][]string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			group := maps.InvertGroup(tc.in)
+			for _, keys := range group {
+				sort.Strings(keys)
+			}
+			if !reflect.DeepEqual(group, tc.group) {
+				t.Errorf(`expected %+v to equal %+v`, group, tc.group)
+			}
+
+			// Without InvertStrict, any of the keys sharing a value may win.
+			out, err := maps.Invert(tc.in)
+			if err != nil || len(out) != len(tc.group) {
+				t.Fatalf(`expected one key per value, but received %+v and error %v`, out, err)
+			}
+			for v, k := range out {
+				if tc.in[k] != v {
+					t.Errorf(`expected %s to map to %d`, k, v)
+				}
+			}
+
+			strict, err := maps.Invert(tc.in, maps.InvertStrict)
+			if tc.strictErr {
+				if err == nil || strict != nil {
+					t.Errorf("expected an error, but received %+v", strict)
+				}
+			} else if err != nil || !reflect.DeepEqual(strict, out) {
+				t.Errorf(`expected %+v to equal %+v, but received error %v`, strict, out, err)
+			}
+		})
+	}
+}
+
+func TestPickAndOmit(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in   map[string]int
+		keys []string
+		pick map[string]int
+		omit map[string]int
+	}{
+		"some keys": {
+			in:   map[string]int{"a": 1, "b": 2, "c": 3},
+			keys: []string{"a", "c"},
+			pick: map[string]int{"a": 1, "c": 3},
+			omit: map[string]int{"b": 2},
+		},
+		"missing keys": {
+			in:   map[string]int{"a": 1, "b": 2},
+			keys: []string{"a", "z"},
+			pick: map[string]int{"a": 1},
+			omit: map[string]int{"b": 2},
+		},
+		"no keys": {
+			in:   map[string]int{"a": 1},
+			keys: []string{},
+			pick: map[string]int{},
+			omit: map[string]int{"a": 1},
+		},
+		"nil input": {
+			in:   nil,
+			keys: []string{"a"},
+			pick: map[string]int{},
+			omit: map[string]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			before := maps.Omit(tc.in)
+
+			if out := maps.Pick(tc.in, tc.keys...); !reflect.DeepEqual(out, tc.pick) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.pick)
+			}
+			if out := maps.Omit(tc.in, tc.keys...); !reflect.DeepEqual(out, tc.omit) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.omit)
+			}
+			if !maps.Equals(tc.in, before) {
+				t.Errorf(`expected %+v to be left unchanged as %+v`, tc.in, before)
+			}
+		})
+	}
+}
+
+func TestGetOr(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  map[string]int
+		key string
+		out int
+		// inserted is the map GetOrInsert returns.
+		inserted map[string]int
+		// called indicates whether the fallback function should run.
+		called bool
+	}{
+		"present": {
+			in:       map[string]int{"a": 1},
+			key:      "a",
+			out:      1,
+			inserted: map[string]int{"a": 1},
+		},
+		"present with zero value": {
+			in:       map[string]int{"a": 0},
+			key:      "a",
+			out:      0,
+			inserted: map[string]int{"a": 0},
+		},
+		"missing": {
+			in:       map[string]int{"a": 1},
+			key:      "b",
+			out:      9,
+			inserted: map[string]int{"a": 1, "b": 9},
+			called:   true,
+		},
+		"nil input": {
+			in:       nil,
+			key:      "b",
+			out:      9,
+			inserted: map[string]int{"b": 9},
+			called:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+			fallback := func() int {
+				calls++
+				return 9
+			}
+
+			if out := maps.GetOr(tc.in, tc.key, 9); out != tc.out {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if out := maps.GetOrElse(tc.in, tc.key, fallback); out != tc.out {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			inserted, out := maps.GetOrInsert(tc.in, tc.key, fallback)
+			if out != tc.out || !reflect.DeepEqual(inserted, tc.inserted) {
+				t.Errorf(`expected %+v and %+v to equal %+v and %+v`, inserted, out, tc.inserted, tc.out)
+			}
+			if _, ok := tc.in[tc.key]; ok == tc.called {
+				t.Errorf(`expected GetOrInsert to leave %+v unchanged`, tc.in)
+			}
+
+			want := 0
+			if tc.called {
+				want = 2
+			}
+			if calls != want {
+				t.Errorf(`expected the fallback to be called %d times, but it was called %d times`, want, calls)
+			}
+		})
+	}
+}
+
+func TestUpdateWith(t *testing.T) {
+	t.Parallel()
+
+	// increment counts how often each key has been seen,
+	// starting from 10 for keys that are not yet present.
+	increment := func(old int, exists bool) int {
+		if !exists {
+			return 10
+		}
+		return old + 1
+	}
+
+	testCases := map[string]struct {
+		in   map[string]int
+		keys []string
+		out  map[string]int
+	}{
+		"existing key": {
+			in:   map[string]int{"a": 1, "b": 2},
+			keys: []string{"a"},
+			out:  map[string]int{"a": 2, "b": 2},
+		},
+		"missing key": {
+			in:   map[string]int{"a": 1},
+			keys: []string{"b"},
+			out:  map[string]int{"a": 1, "b": 10},
+		},
+		"several keys": {
+			in:   map[string]int{"a": 1, "b": 2},
+			keys: []string{"a", "b", "c"},
+			out:  map[string]int{"a": 2, "b": 3, "c": 10},
+		},
+		"no keys": {
+			in:   map[string]int{"a": 1},
+			keys: []string{},
+			out:  map[string]int{"a": 1},
+		},
+		"nil input": {
+			in:   nil,
+			keys: []string{"a"},
+			out:  map[string]int{"a": 10},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			before := maps.Omit(tc.in)
+
+			out := maps.UpdateAll(tc.in, func(_ string, old int, exists bool) int {
+				return increment(old, exists)
+			}, tc.keys...)
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+
+			if len(tc.keys) == 1 {
+				if out := maps.UpdateWith(tc.in, tc.keys[0], increment); !reflect.DeepEqual(out, tc.out) {
+					t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+				}
+			}
+
+			if !maps.Equals(tc.in, before) {
+				t.Errorf(`expected %+v to be left unchanged as %+v`, tc.in, before)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		before, after           map[string]int
+		added, removed, changed map[string]int
+	}{
+		"every kind of change": {
+			before:  map[string]int{"a": 1, "b": 2, "c": 3},
+			after:   map[string]int{"a": 1, "b": 20, "d": 4},
+			added:   map[string]int{"d": 4},
+			removed: map[string]int{"c": 3},
+			changed: map[string]int{"b": 20},
+		},
+		"identical": {
+			before:  map[string]int{"a": 1},
+			after:   map[string]int{"a": 1},
+			added:   map[string]int{},
+			removed: map[string]int{},
+			changed: map[string]int{},
+		},
+		"nil before": {
+			before:  nil,
+			after:   map[string]int{"a": 1},
+			added:   map[string]int{"a": 1},
+			removed: map[string]int{},
+			changed: map[string]int{},
+		},
+		"nil after": {
+			before:  map[string]int{"a": 1},
+			after:   nil,
+			added:   map[string]int{},
+			removed: map[string]int{"a": 1},
+			changed: map[string]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			added, removed, changed := maps.Diff(tc.before, tc.after)
+			out := []map[string]int{added, removed, changed}
+			want := []map[string]int{tc.added, tc.removed, tc.changed}
+			if !reflect.DeepEqual(out, want) {
+				t.Errorf(`expected %+v to equal %+v`, out, want)
+			}
+		})
+	}
+
+	t.Run("by equality function", func(t *testing.T) {
+		t.Parallel()
+
+		before := map[string][]int{"a": {1, 2}, "b": {3}}
+		after := map[string][]int{"a": {1, 2}, "b": {4}}
+		added, removed, changed := maps.DiffBy(before, after, func(a, b []int) bool {
+			return reflect.DeepEqual(a, b)
+		})
+
+		out := []map[string][]int{added, removed, changed}
+		want := []map[string][]int{{}, {}, {"b": {4}}}
+		if !reflect.DeepEqual(out, want) {
+			t.Errorf(`expected %+v to equal %+v`, out, want)
+		}
+	})
+}
+
+func TestSorted(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in   map[string]int
+		keys []string
+		// byValue is the pairs of in sorted by descending value.
+		byValue []pairs.Pair[string, int]
+	}{
+		"simple case": {
+			in:      map[string]int{"b": 1, "c": 3, "a": 2},
+			keys:    []string{"a", "b", "c"},
+			byValue: []pairs.Pair[string, int]{pairs.New("c", 3), pairs.New("a", 2), pairs.New("b", 1)},
+		},
+		"empty input": {
+			in:      map[string]int{},
+			keys:    []string{},
+			byValue: []pairs.Pair[string, int]{},
+		},
+		"nil input": {
+			in:      nil,
+			keys:    []string{},
+			byValue: []pairs.Pair[string, int]{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out := maps.SortedKeys(tc.in); !reflect.DeepEqual(out, tc.keys) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.keys)
+			}
+
+			out := maps.SortedPairs(tc.in, func(a, b pairs.Pair[string, int]) bool {
+				return a.Right > b.Right
+			})
+			if !reflect.DeepEqual(out, tc.byValue) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.byValue)
+			}
+
+			visited := []string{}
+			maps.ForEachSorted(tc.in, func(k string, v int) {
+				if tc.in[k] != v {
+					t.Errorf(`expected %s to be visited with %d, but got %d`, k, tc.in[k], v)
+				}
+				visited = append(visited, k)
+			})
+			if !reflect.DeepEqual(visited, tc.keys) {
+				t.Errorf(`expected %+v to equal %+v`, visited, tc.keys)
+			}
+		})
+	}
+}
+
+func TestTally(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in    map[string]int
+		tally map[int]int
+		// byParity counts the key value pairs by whether the value is even.
+		byParity map[bool]int
+	}{
+		"simple case": {
+			in:       map[string]int{"a": 1, "b": 2, "c": 1, "d": 4},
+			tally:    map[int]int{1: 2, 2: 1, 4: 1},
+			byParity: map[bool]int{false: 2, true: 2},
+		},
+		"empty input": {
+			in:       map[string]int{},
+			tally:    map[int]int{},
+			byParity: map[bool]int{},
+		},
+		"nil input": {
+			in:       nil,
+			tally:    map[int]int{},
+			byParity: map[bool]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out := maps.TallyValues(tc.in); !reflect.DeepEqual(out, tc.tally) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.tally)
+			}
+
+			out := maps.CountBy(tc.in, func(_ string, v int) bool {
+				return v%2 == 0
+			})
+			if !reflect.DeepEqual(out, tc.byParity) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.byParity)
+			}
+		})
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  map[string]int
+		out map[bool]map[string]int
+	}{
+		"simple case": {
+			in: map[string]int{"a": 1, "b": 2, "c": 3},
+			out: map[bool]map[string]int{
+				false: {"a": 1, "c": 3},
+				true:  {"b": 2},
+			},
+		},
+		"single group": {
+			in:  map[string]int{"a": 2, "b": 4},
+			out: map[bool]map[string]int{true: {"a": 2, "b": 4}},
+		},
+		"empty input": {
+			in:  map[string]int{},
+			out: map[bool]map[string]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := maps.GroupBy(tc.in, func(_ string, v int) bool {
+				return v%2 == 0
+			})
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestFlatMapToSlice(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  map[string]int
+		out []string
+	}{
+		"simple case": {
+			in:  map[string]int{"a": 2, "b": 1},
+			out: []string{"a", "a", "b"},
+		},
+		"empty results": {
+			in:  map[string]int{"a": 0, "b": 1},
+			out: []string{"b"},
+		},
+		"nil input": {
+			in:  nil,
+			out: []string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := maps.FlatMapToSlice(tc.in, func(k string, v int) []string {
+				result := []string{}
+				for i := 0; i < v; i++ {
+					result = append(result, k)
+				}
+				return result
+			})
+			sort.Strings(out)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestTry(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	testCases := map[string]struct {
+		in map[string]int
+		// fail is the value for which fn fails, or zero for none.
+		fail     int
+		mapped   map[int]string
+		filtered map[string]int
+	}{
+		"no errors": {
+			in:       map[string]int{"a": 1, "b": 2, "c": 3},
+			mapped:   map[int]string{1: "a", 2: "b", 3: "c"},
+			filtered: map[string]int{"a": 1, "c": 3},
+		},
+		"error": {
+			in:   map[string]int{"a": 1, "b": 2, "c": 3},
+			fail: 2,
+		},
+		"empty input": {
+			in:       map[string]int{},
+			mapped:   map[int]string{},
+			filtered: map[string]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			check := func(v int) error {
+				if v == tc.fail {
+					return errBoom
+				}
+				return nil
+			}
+			wantErr := error(nil)
+			if tc.fail != 0 {
+				wantErr = errBoom
+			}
+
+			mapped, err := maps.TryMap(tc.in, func(k string, v int) (int, string, error) {
+				return v, k, check(v)
+			})
+			if !reflect.DeepEqual(mapped, tc.mapped) || !errors.Is(err, wantErr) {
+				t.Errorf(`expected %+v and %v to equal %+v and %v`, mapped, err, tc.mapped, wantErr)
+			}
+
+			filtered, err := maps.TryFilter(tc.in, func(_ string, v int) (bool, error) {
+				return v%2 == 1, check(v)
+			})
+			if !reflect.DeepEqual(filtered, tc.filtered) || !errors.Is(err, wantErr) {
+				t.Errorf(`expected %+v and %v to equal %+v and %v`, filtered, err, tc.filtered, wantErr)
+			}
+
+			visited := 0
+			err = maps.TryForEach(tc.in, func(_ string, v int) error {
+				visited++
+				return check(v)
+			})
+			if !errors.Is(err, wantErr) {
+				t.Errorf(`expected %v to be %v`, err, wantErr)
+			}
+			if err == nil && visited != len(tc.in) {
+				t.Errorf(`expected every entry to be visited, but only %d were`, visited)
+			}
+		})
+	}
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		t.Parallel()
+
+		visited := 0
+		err := maps.TryForEach(map[string]int{"a": 1, "b": 2, "c": 3}, func(string, int) error {
+			visited++
+			return errBoom
+		})
+		if !errors.Is(err, errBoom) || visited != 1 {
+			t.Errorf(`expected to stop after one entry with %v, but visited %d and got %v`, errBoom, visited, err)
+		}
+	})
+}
+
+func TestSample(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  map[string]int
+		idx int
+		err bool
+	}{
+		"first index": {
+			in:  map[string]int{"a": 1, "b": 2, "c": 3},
+			idx: 0,
+		},
+		"last index": {
+			in:  map[string]int{"a": 1, "b": 2, "c": 3},
+			idx: 2,
+		},
+		"index too large": {
+			in:  map[string]int{"a": 1, "b": 2},
+			idx: 2,
+			err: true,
+		},
+		"negative index": {
+			in:  map[string]int{"a": 1},
+			idx: -1,
+			err: true,
+		},
+		"empty input": {
+			in:  map[string]int{},
+			err: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k, v, err := maps.Sample(tc.in, maps.WithRandom(func(int) int {
+				return tc.idx
+			}))
+
+			if tc.err {
+				if err == nil {
+					t.Errorf("expected an error, but received %s and %d", k, v)
+				}
+				return
+			}
+			if got, ok := tc.in[k]; err != nil || !ok || got != v {
+				t.Errorf(`expected an entry of %+v, but received %s and %d with error %v`, tc.in, k, v, err)
+			}
+		})
+	}
+}
+
+func TestTakeRandom(t *testing.T) {
+	t.Parallel()
+
+	in := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+
+	testCases := map[string]struct {
+		num  int
+		size int
+	}{
+		"some":      {num: 2, size: 2},
+		"all":       {num: 4, size: 4},
+		"more than": {num: 10, size: 4},
+		"none":      {num: 0, size: 0},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Always choosing the last remaining key exercises
+			// the edge of the range WithRandom may return.
+			out := maps.TakeRandom(in, tc.num, maps.WithRandom(func(n int) int {
+				return n - 1
+			}))
+
+			if len(out) != tc.size {
+				t.Errorf(`expected %d entries, but received %+v`, tc.size, out)
+			}
+			for k, v := range out {
+				if in[k] != v {
+					t.Errorf(`expected %s to map to %d, but got %d`, k, in[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	t.Parallel()
+
+	in := map[string]int{"a": 1, "b": 2, "c": 1}
+
+	testCases := map[string]struct {
+		keys      []string
+		values    []int
+		allKeys   bool
+		anyKeys   bool
+		allValues bool
+	}{
+		"all present": {
+			keys:      []string{"a", "c"},
+			values:    []int{1, 2},
+			allKeys:   true,
+			anyKeys:   true,
+			allValues: true,
+		},
+		"some present": {
+			keys:      []string{"a", "z"},
+			values:    []int{1, 9},
+			allKeys:   false,
+			anyKeys:   true,
+			allValues: false,
+		},
+		"none present": {
+			keys:      []string{"y", "z"},
+			values:    []int{8, 9},
+			allKeys:   false,
+			anyKeys:   false,
+			allValues: false,
+		},
+		"repeated arguments": {
+			keys:      []string{"a", "a"},
+			values:    []int{1, 1},
+			allKeys:   true,
+			anyKeys:   true,
+			allValues: true,
+		},
+		"no arguments": {
+			keys:      []string{},
+			values:    []int{},
+			allKeys:   true,
+			anyKeys:   false,
+			allValues: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out := maps.ContainsAllKeys(in, tc.keys...); out != tc.allKeys {
+				t.Errorf(`expected ContainsAllKeys to report %v, but got %v`, tc.allKeys, out)
+			}
+			if out := maps.ContainsAnyKeys(in, tc.keys...); out != tc.anyKeys {
+				t.Errorf(`expected ContainsAnyKeys to report %v, but got %v`, tc.anyKeys, out)
+			}
+			if out := maps.ContainsAllValues(in, tc.values...); out != tc.allValues {
+				t.Errorf(`expected ContainsAllValues to report %v, but got %v`, tc.allValues, out)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in map[string]int
+		fn func(string, int) bool
+		// matches are the entries Find may return.
+		matches map[string]int
+	}{
+		"single match": {
+			in:      map[string]int{"a": 1, "b": 2, "c": 3},
+			fn:      func(_ string, v int) bool { return v == 2 },
+			matches: map[string]int{"b": 2},
+		},
+		"several matches": {
+			in:      map[string]int{"a": 1, "b": 2, "c": 3},
+			fn:      func(_ string, v int) bool { return v != 2 },
+			matches: map[string]int{"a": 1, "c": 3},
+		},
+		"no match": {
+			in:      map[string]int{"a": 1},
+			fn:      func(string, int) bool { return false },
+			matches: map[string]int{},
+		},
+		"nil input": {
+			in:      nil,
+			fn:      func(string, int) bool { return true },
+			matches: map[string]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k, v, ok := maps.Find(tc.in, tc.fn)
+			if ok != (len(tc.matches) > 0) {
+				t.Fatalf(`expected Find to report %v, but got %v`, len(tc.matches) > 0, ok)
+			}
+			if !ok {
+				if k != "" || v != 0 {
+					t.Errorf(`expected zero values, but received %s and %d`, k, v)
+				}
+				return
+			}
+			if want, found := tc.matches[k]; !found || want != v {
+				t.Errorf(`expected one of %+v, but received %s and %d`, tc.matches, k, v)
+			}
+		})
+	}
+}
+
+func TestTakeAndDropSorted(t *testing.T) {
+	t.Parallel()
+
+	in := map[int]string{3: "c", 1: "a", 4: "d", 2: "b"}
+	below3 := func(k int, _ string) bool { return k < 3 }
+
+	testCases := map[string]struct {
+		num  int
+		fn   func(int, string) bool
+		take map[int]string
+		drop map[int]string
+	}{
+		"some": {
+			num:  2,
+			fn:   below3,
+			take: map[int]string{1: "a", 2: "b"},
+			drop: map[int]string{3: "c", 4: "d"},
+		},
+		"none": {
+			num:  0,
+			fn:   func(int, string) bool { return false },
+			take: map[int]string{},
+			drop: in,
+		},
+		"all": {
+			num:  10,
+			fn:   func(int, string) bool { return true },
+			take: in,
+			drop: map[int]string{},
+		},
+		"negative": {
+			num:  -1,
+			fn:   func(int, string) bool { return false },
+			take: map[int]string{},
+			drop: in,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out := maps.TakeSorted(in, tc.num); !reflect.DeepEqual(out, tc.take) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.take)
+			}
+			if out := maps.DropSorted(in, tc.num); !reflect.DeepEqual(out, tc.drop) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.drop)
+			}
+			if out := maps.TakeWhileSorted(in, tc.fn); !reflect.DeepEqual(out, tc.take) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.take)
+			}
+			if out := maps.DropWhileSorted(in, tc.fn); !reflect.DeepEqual(out, tc.drop) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.drop)
+			}
+		})
+	}
+
+	t.Run("while stops at the first failure", func(t *testing.T) {
+		t.Parallel()
+
+		// 3 fails, so 4 is never considered even though it passes.
+		fn := func(k int, _ string) bool { return k != 3 }
+		if out := maps.TakeWhileSorted(in, fn); !reflect.DeepEqual(out, map[int]string{1: "a", 2: "b"}) {
+			t.Errorf(`expected %+v to equal %+v`, out, map[int]string{1: "a", 2: "b"})
+		}
+		if out := maps.DropWhileSorted(in, fn); !reflect.DeepEqual(out, map[int]string{3: "c", 4: "d"}) {
+			t.Errorf(`expected %+v to equal %+v`, out, map[int]string{3: "c", 4: "d"})
+		}
+	})
+}
+
+func TestReduceSortedAndWhile(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in     map[string]int
+		sorted string
+		// limit is the total at which ReduceWhile stops.
+		limit int
+		while int
+	}{
+		"simple case": {
+			in:     map[string]int{"b": 2, "c": 3, "a": 1},
+			sorted: "a1b2c3",
+			limit:  100,
+			while:  6,
+		},
+		"stops early": {
+			in:     map[string]int{"a": 5, "b": 5, "c": 5},
+			sorted: "a5b5c5",
+			limit:  10,
+			while:  10,
+		},
+		"empty input": {
+			in:     map[string]int{},
+			sorted: "",
+			limit:  10,
+			while:  0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Concatenation is not commutative, so only
+			// a fixed visiting order gives a stable result.
+			sorted := maps.ReduceSorted(tc.in, "", func(acc string, k string, v int) string {
+				return acc + k + strconv.Itoa(v)
+			})
+			if sorted != tc.sorted {
+				t.Errorf(`expected %+v to equal %+v`, sorted, tc.sorted)
+			}
+
+			calls := 0
+			while := maps.ReduceWhile(tc.in, 0, func(acc int, _ string, v int) (int, bool) {
+				calls++
+				acc += v
+				return acc, acc < tc.limit
+			})
+			if while != tc.while {
+				t.Errorf(`expected %+v to equal %+v`, while, tc.while)
+			}
+			if tc.while >= tc.limit && calls != 2 {
+				t.Errorf(`expected ReduceWhile to stop once the limit was reached, but fn was called %d times`, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package maps
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Memo is a map whose values are computed on first access by a
+// user-provided function and cached for subsequent accesses.
+// The cache can be bounded with MemoMaxSize, in which case
+// the least recently used entries are evicted first.
+// A Memo is only safe for concurrent use if created with MemoConcurrent.
+type Memo[K comparable, V any] struct {
+	// fn computes the value for a key that is not yet cached.
+	fn func(K) V
+	// args holds the optional arguments the Memo was created with.
+	args memoArgs
+	// mu guards entries and order if args.concurrent is set.
+	mu sync.Mutex
+	// entries maps each cached key to its place in order.
+	entries map[K]*list.Element
+	// order holds the cached entries from most to least recently used.
+	order *list.List
+}
+
+// memoEntry is a single cached value in a Memo.
+type memoEntry[K comparable, V any] struct {
+	key   K
+	value V
+	// done is closed once value has been computed.
+	// It is only used if the Memo is concurrent.
+	done chan struct{}
+	// ok indicates whether value was computed without panicking.
+	ok bool
+}
+
+// memoArgs represent optional arguments to NewMemo.
+type memoArgs struct {
+	// maxSize bounds the number of cached entries.
+	// A value of zero or less means the cache is unbounded.
+	maxSize int
+	// concurrent indicates whether the Memo is safe for concurrent use.
+	concurrent bool
+}
+
+// MemoOpt represent optional arguments to NewMemo.
+type MemoOpt func(*memoArgs)
+
+// MemoConcurrent is a MemoOpt that makes the Memo safe for concurrent use.
+// Concurrent calls to Get for the same uncached key compute its value once,
+// with all callers waiting for the result. Values for different keys
+// are computed in parallel.
+func MemoConcurrent(o *memoArgs) {
+	o.concurrent = true
+}
+
+// MemoMaxSize is a MemoOpt that bounds the cache to n entries,
+// evicting the least recently used entry when it is exceeded.
+// If n is zero or less, the cache is unbounded.
+func MemoMaxSize(n int) MemoOpt {
+	return func(o *memoArgs) {
+		o.maxSize = n
+	}
+}
+
+// NewMemo creates a new Memo that computes the value for each key with fn.
+func NewMemo[K comparable, V any](fn func(K) V, opts ...MemoOpt) *Memo[K, V] {
+	args := memoArgs{}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	return &Memo[K, V]{
+		fn:      fn,
+		args:    args,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Forget removes the cached value for k, if any,
+// so that the next call to Get computes it again.
+func (m *Memo[K, V]) Forget(k K) {
+	m.lock()
+	defer m.unlock()
+
+	if el, ok := m.entries[k]; ok {
+		m.order.Remove(el)
+		delete(m.entries, k)
+	}
+}
+
+// Get returns the value for k, computing and caching it if necessary.
+// If the computation panics, nothing is cached and the panic propagates.
+func (m *Memo[K, V]) Get(k K) V {
+	m.lock()
+	if el, ok := m.entries[k]; ok {
+		m.order.MoveToFront(el)
+		entry := el.Value.(*memoEntry[K, V])
+		m.unlock()
+
+		if !m.args.concurrent {
+			return entry.value
+		}
+
+		<-entry.done
+		if !entry.ok {
+			return m.Get(k)
+		}
+		return entry.value
+	}
+
+	entry := &memoEntry[K, V]{key: k}
+	if m.args.concurrent {
+		entry.done = make(chan struct{})
+	}
+	m.entries[k] = m.order.PushFront(entry)
+	m.evict()
+	m.unlock()
+
+	defer func() {
+		if !entry.ok {
+			m.lock()
+			if el, ok := m.entries[k]; ok && el.Value == entry {
+				m.order.Remove(el)
+				delete(m.entries, k)
+			}
+			m.unlock()
+		}
+		if entry.done != nil {
+			close(entry.done)
+		}
+	}()
+
+	entry.value = m.fn(k)
+	entry.ok = true
+
+	return entry.value
+}
+
+// Len returns the number of cached values in m.
+func (m *Memo[K, V]) Len() int {
+	m.lock()
+	defer m.unlock()
+
+	return len(m.entries)
+}
+
+// Peek returns the cached value for k without computing it,
+// or false if it is not cached or is still being computed.
+// It does not count as a use of k when deciding which entry to evict.
+func (m *Memo[K, V]) Peek(k K) (V, bool) {
+	m.lock()
+	el, ok := m.entries[k]
+	m.unlock()
+
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	entry := el.Value.(*memoEntry[K, V])
+	if m.args.concurrent {
+		select {
+		case <-entry.done:
+		default:
+			var zero V
+			return zero, false
+		}
+	}
+	if !entry.ok {
+		var zero V
+		return zero, false
+	}
+
+	return entry.value, true
+}
+
+// evict removes the least recently used entries
+// until m no longer exceeds its maximum size.
+// The caller must hold the lock.
+func (m *Memo[K, V]) evict() {
+	if m.args.maxSize <= 0 {
+		return
+	}
+
+	for len(m.entries) > m.args.maxSize {
+		el := m.order.Back()
+		m.order.Remove(el)
+		delete(m.entries, el.Value.(*memoEntry[K, V]).key)
+	}
+}
+
+// lock acquires the lock on m if it is concurrent.
+func (m *Memo[K, V]) lock() {
+	if m.args.concurrent {
+		m.mu.Lock()
+	}
+}
+
+// unlock releases the lock on m if it is concurrent.
+func (m *Memo[K, V]) unlock() {
+	if m.args.concurrent {
+		m.mu.Unlock()
+	}
+}
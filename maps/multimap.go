@@ -0,0 +1,145 @@
+package maps
+
+import (
+	"github.com/mcmathja/funky/pairs"
+)
+
+// The functions in this file operate on multimaps: maps from each key
+// to a slice of values, such as those produced by slices.GroupBy.
+// Like the rest of the package, they never modify their inputs.
+
+// AppendValue creates a copy of m in which
+// vs are appended to the values for k.
+func AppendValue[K comparable, V any](m map[K][]V, k K, vs ...V) map[K][]V {
+	result := copyMulti(m)
+	values := make([]V, 0, len(m[k])+len(vs))
+	values = append(values, m[k]...)
+	result[k] = append(values, vs...)
+
+	return result
+}
+
+// FromBatchMulti creates a new multimap from each key produced
+// by b to all of its values, in the order they are produced.
+func FromBatchMulti[K comparable, V any](b func(func(pairs.Pair[K, V]))) map[K][]V {
+	result := make(map[K][]V)
+	b(func(kv pairs.Pair[K, V]) {
+		result[kv.Left] = append(result[kv.Left], kv.Right)
+	})
+
+	return result
+}
+
+// FromChanMulti creates a new multimap from each key received
+// on ch to all of its values, in the order they are received.
+// It only returns its results once the channel closes.
+func FromChanMulti[K comparable, V any](ch <-chan pairs.Pair[K, V]) map[K][]V {
+	result := make(map[K][]V)
+	for kv := range ch {
+		result[kv.Left] = append(result[kv.Left], kv.Right)
+	}
+
+	return result
+}
+
+// FromSliceMulti creates a new multimap from each key
+// in s to all of its values, in order.
+func FromSliceMulti[K comparable, V any](s []pairs.Pair[K, V]) map[K][]V {
+	result := make(map[K][]V)
+	for _, kv := range s {
+		result[kv.Left] = append(result[kv.Left], kv.Right)
+	}
+
+	return result
+}
+
+// FlattenValues returns every value in m. Values for the same key
+// keep their order, but the keys are visited in an arbitrary order.
+func FlattenValues[K comparable, V any](m map[K][]V) []V {
+	result := make([]V, 0)
+	for _, vs := range m {
+		result = append(result, vs...)
+	}
+
+	return result
+}
+
+// InvertMulti creates a new multimap from each value in m
+// to the keys it appears under, in an arbitrary order.
+// A key appears once for each time the value appears under it.
+func InvertMulti[K, V comparable](m map[K][]V) map[V][]K {
+	result := make(map[V][]K)
+	for k, vs := range m {
+		for _, v := range vs {
+			result[v] = append(result[v], k)
+		}
+	}
+
+	return result
+}
+
+// MapValuesEach creates a new multimap in which each value
+// in m is replaced with the result of applying fn to it and its key.
+func MapValuesEach[K comparable, V, U any](m map[K][]V, fn func(K, V) U) map[K][]U {
+	result := make(map[K][]U, len(m))
+	for k, vs := range m {
+		us := make([]U, 0, len(vs))
+		for _, v := range vs {
+			us = append(us, fn(k, v))
+		}
+		result[k] = us
+	}
+
+	return result
+}
+
+// MergeMulti creates a new multimap in which the values for each key
+// are the values for that key in each of ms, concatenated in order.
+func MergeMulti[K comparable, V any](ms ...map[K][]V) map[K][]V {
+	result := make(map[K][]V)
+	for _, m := range ms {
+		for k, vs := range m {
+			result[k] = append(result[k], vs...)
+		}
+	}
+
+	return result
+}
+
+// RemoveValue creates a copy of m with every occurrence of v removed
+// from the values for k. If no values remain for k, k is removed.
+func RemoveValue[K, V comparable](m map[K][]V, k K, v V) map[K][]V {
+	result := copyMulti(m)
+	values, ok := m[k]
+	if !ok {
+		return result
+	}
+
+	kept := make([]V, 0, len(values))
+	for _, value := range values {
+		if value != v {
+			kept = append(kept, value)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(result, k)
+	} else {
+		result[k] = kept
+	}
+
+	return result
+}
+
+/* Helpers */
+
+// copyMulti creates a shallow copy of m. The value slices
+// are shared, so they must be copied before modification.
+func copyMulti[K comparable, V any](m map[K][]V) map[K][]V {
+	result := make(map[K][]V, len(m))
+	for k, vs := range m {
+		result[k] = vs
+	}
+
+	return result
+}
@@ -0,0 +1,127 @@
+package maps_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/mcmathja/funky/maps"
+	"github.com/mcmathja/funky/pairs"
+)
+
+func TestMultimap(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fn  func(m map[string][]int) map[string][]int
+		in  map[string][]int
+		out map[string][]int
+	}{
+		"append to existing key": {
+			fn: func(m map[string][]int) map[string][]int {
+				return maps.AppendValue(m, "a", 3, 4)
+			},
+			in:  map[string][]int{"a": make([]int, 2, 10), "b": {5}},
+			out: map[string][]int{"a": {0, 0, 3, 4}, "b": {5}},
+		},
+		"append to missing key": {
+			fn: func(m map[string][]int) map[string][]int {
+				return maps.AppendValue(m, "c", 1)
+			},
+			in:  map[string][]int{"a": {1}},
+			out: map[string][]int{"a": {1}, "c": {1}},
+		},
+		"remove every occurrence": {
+			fn: func(m map[string][]int) map[string][]int {
+				return maps.RemoveValue(m, "a", 1)
+			},
+			in:  map[string][]int{"a": {1, 2, 1}, "b": {1}},
+			out: map[string][]int{"a": {2}, "b": {1}},
+		},
+		"remove last value drops key": {
+			fn: func(m map[string][]int) map[string][]int {
+				return maps.RemoveValue(m, "a", 1)
+			},
+			in:  map[string][]int{"a": {1}, "b": {2}},
+			out: map[string][]int{"b": {2}},
+		},
+		"remove from missing key": {
+			fn: func(m map[string][]int) map[string][]int {
+				return maps.RemoveValue(m, "z", 1)
+			},
+			in:  map[string][]int{"a": {1}},
+			out: map[string][]int{"a": {1}},
+		},
+		"map values": {
+			fn: func(m map[string][]int) map[string][]int {
+				return maps.MapValuesEach(m, func(k string, v int) int {
+					return len(k) * v * 10
+				})
+			},
+			in:  map[string][]int{"a": {1, 2}, "bb": {3}},
+			out: map[string][]int{"a": {10, 20}, "bb": {60}},
+		},
+		"merge": {
+			fn: func(m map[string][]int) map[string][]int {
+				return maps.MergeMulti(m, map[string][]int{"a": {9}, "c": {8}})
+			},
+			in:  map[string][]int{"a": {1}, "b": {2}},
+			out: map[string][]int{"a": {1, 9}, "b": {2}, "c": {8}},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			before := make(map[string][]int, len(tc.in))
+			for k, vs := range tc.in {
+				before[k] = append([]int{}, vs...)
+			}
+
+			if out := tc.fn(tc.in); !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+
+			// The spare capacity of the inputs starts out zeroed, so a
+			// non-zero element there means a shared array was written to.
+			for k, vs := range tc.in {
+				spare := vs[len(vs):cap(vs)]
+				if !reflect.DeepEqual(vs, before[k]) || !reflect.DeepEqual(spare, make([]int, len(spare))) {
+					t.Errorf(`expected the values for %s to be left unchanged as %+v, but got %+v`, k, before[k], vs[:cap(vs)])
+				}
+			}
+		})
+	}
+}
+
+func TestMultimapConversions(t *testing.T) {
+	t.Parallel()
+
+	in := []pairs.Pair[string, int]{pairs.New("a", 1), pairs.New("b", 2), pairs.New("a", 3)}
+	want := map[string][]int{"a": {1, 3}, "b": {2}}
+
+	ch := make(chan pairs.Pair[string, int], len(in))
+	for _, kv := range in {
+		ch <- kv
+	}
+	close(ch)
+	if out := maps.FromChanMulti(ch); !reflect.DeepEqual(out, want) {
+		t.Errorf(`expected %+v to equal %+v`, out, want)
+	}
+
+	flat := maps.FlattenValues(want)
+	sort.Ints(flat)
+	if !reflect.DeepEqual(flat, []int{1, 2, 3}) {
+		t.Errorf(`expected %+v to equal %+v`, flat, []int{1, 2, 3})
+	}
+
+	inverted := maps.InvertMulti(map[string][]int{"a": {1, 2, 1}, "b": {1}})
+	for _, keys := range inverted {
+		sort.Strings(keys)
+	}
+	if want := map[int][]string{1: {"a", "a", "b"}, 2: {"a"}}; !reflect.DeepEqual(inverted, want) {
+		t.Errorf(`expected %+v to equal %+v`, inverted, want)
+	}
+}
@@ -0,0 +1,154 @@
+package maps
+
+import (
+	"sync"
+
+	"github.com/mcmathja/funky/pairs"
+)
+
+// Sync is a map that is safe for concurrent use. It is a typed
+// wrapper around sync.Map and shares its performance characteristics:
+// it is best suited to keys that are written once and read many times,
+// or to goroutines that work on disjoint sets of keys.
+// The zero value is an empty Sync ready to use.
+// A Sync must not be copied after first use.
+// Values stored in it may be nil, even if V is an interface type.
+//
+// Operations that would otherwise have to range over the map,
+// such as Filter and SyncReduce, work on a snapshot of its contents.
+// A snapshot is not taken atomically: it reflects some state of each
+// key at some point during the call, not of the map as a whole.
+type Sync[K comparable, V any] struct {
+	m sync.Map
+}
+
+// NewSync creates a new Sync containing the provided key value pairs.
+// If the same key is repeated twice, the last value wins.
+func NewSync[K comparable, V any](kvs ...pairs.Pair[K, V]) *Sync[K, V] {
+	result := &Sync[K, V]{}
+	for _, kv := range kvs {
+		result.Set(kv.Left, kv.Right)
+	}
+
+	return result
+}
+
+// SyncFromMap creates a new Sync containing the key value pairs in m.
+func SyncFromMap[K comparable, V any](m map[K]V) *Sync[K, V] {
+	result := &Sync[K, V]{}
+	for k, v := range m {
+		result.Set(k, v)
+	}
+
+	return result
+}
+
+// Delete removes k from s, if present.
+func (s *Sync[K, V]) Delete(k K) {
+	s.m.Delete(k)
+}
+
+// Filter returns a snapshot of the key value pairs in s that satisfy fn.
+func (s *Sync[K, V]) Filter(fn func(K, V) bool) map[K]V {
+	result := make(map[K]V)
+	s.ForEach(func(k K, v V) {
+		if fn(k, v) {
+			result[k] = v
+		}
+	})
+
+	return result
+}
+
+// ForEach performs fn on each key value pair in s.
+// fn may modify s, but no key is visited more than once.
+func (s *Sync[K, V]) ForEach(fn func(key K, value V)) {
+	s.m.Range(func(k, v any) bool {
+		key, _ := k.(K)
+		value, _ := v.(V)
+		fn(key, value)
+		return true
+	})
+}
+
+// Get returns the value for k, or false if it is not present.
+func (s *Sync[K, V]) Get(k K) (V, bool) {
+	v, ok := s.m.Load(k)
+	value, _ := v.(V)
+
+	return value, ok
+}
+
+// GetAndDelete removes k from s, returning its previous value,
+// or false if it was not present.
+func (s *Sync[K, V]) GetAndDelete(k K) (V, bool) {
+	v, ok := s.m.LoadAndDelete(k)
+	value, _ := v.(V)
+
+	return value, ok
+}
+
+// GetOrInsert returns the value for k if it is present.
+// Otherwise, it sets the value for k to v and returns v.
+// The boolean reports whether the value was already present.
+func (s *Sync[K, V]) GetOrInsert(k K, v V) (V, bool) {
+	actual, loaded := s.m.LoadOrStore(k, v)
+	value, _ := actual.(V)
+
+	return value, loaded
+}
+
+// Has checks whether k is present in s.
+func (s *Sync[K, V]) Has(k K) bool {
+	_, ok := s.m.Load(k)
+	return ok
+}
+
+// Len returns the number of key value pairs in s.
+// It ranges over s, so it takes time proportional to its size.
+func (s *Sync[K, V]) Len() int {
+	cnt := 0
+	s.m.Range(func(_, _ any) bool {
+		cnt++
+		return true
+	})
+
+	return cnt
+}
+
+// Set sets the value for k to v.
+func (s *Sync[K, V]) Set(k K, v V) {
+	s.m.Store(k, v)
+}
+
+// Snapshot returns a plain map containing the key value pairs in s.
+func (s *Sync[K, V]) Snapshot() map[K]V {
+	result := make(map[K]V)
+	s.ForEach(func(k K, v V) {
+		result[k] = v
+	})
+
+	return result
+}
+
+// SyncMapValues returns a snapshot of s in which
+// fn has been applied to each of the values.
+func SyncMapValues[K comparable, V, U any](s *Sync[K, V], fn func(K, V) U) map[K]U {
+	result := make(map[K]U)
+	s.ForEach(func(k K, v V) {
+		result[k] = fn(k, v)
+	})
+
+	return result
+}
+
+// SyncReduce reduces a snapshot of s to a single value
+// by applying fn to each key value pair, in an arbitrary order.
+func SyncReduce[K comparable, V, U any](s *Sync[K, V], initial U, fn func(U, K, V) U) U {
+	result := initial
+	s.ForEach(func(k K, v V) {
+		result = fn(result, k, v)
+	})
+
+	return result
+}
@@ -0,0 +1,98 @@
+package maps_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/mcmathja/funky/maps"
+	"github.com/mcmathja/funky/pairs"
+)
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	s := maps.NewSync(pairs.New("a", 1), pairs.New("b", 2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s.Set("k"+strconv.Itoa(i), i+3)
+		}(i)
+	}
+	wg.Wait()
+
+	if s.Len() != 52 {
+		t.Errorf("expected 52 elements, but received %d", s.Len())
+	}
+	if v, ok := s.Get("a"); !ok || v != 1 {
+		t.Errorf("expected 1, true, but received %d, %t", v, ok)
+	}
+	if v, ok := s.GetOrInsert("a", 9); !ok || v != 1 {
+		t.Errorf("expected 1, true, but received %d, %t", v, ok)
+	}
+	if v, ok := s.GetOrInsert("z", 9); ok || v != 9 {
+		t.Errorf("expected 9, false, but received %d, %t", v, ok)
+	}
+	if v, ok := s.GetAndDelete("z"); !ok || v != 9 || s.Has("z") {
+		t.Errorf("expected z to be removed with value 9, but received %d, %t", v, ok)
+	}
+	if _, ok := s.GetAndDelete("z"); ok {
+		t.Errorf("expected z to be absent")
+	}
+
+	filtered := s.Filter(func(_ string, v int) bool { return v <= 2 })
+	if !maps.Equals(filtered, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("expected %+v to equal %+v", filtered, map[string]int{"a": 1, "b": 2})
+	}
+	if sum := maps.SyncReduce(s, 0, func(acc int, _ string, v int) int { return acc + v }); sum != 1378 {
+		t.Errorf("expected a sum of 1378, but received %d", sum)
+	}
+	if m := maps.SyncMapValues(s, func(_ string, v int) bool { return v%2 == 0 }); len(m) != 52 || m["a"] || !m["b"] {
+		t.Errorf("unexpected result %+v", m)
+	}
+	if !maps.Equals(maps.SyncFromMap(s.Snapshot()).Snapshot(), s.Snapshot()) {
+		t.Errorf("expected snapshots to be equal")
+	}
+}
+
+func TestSyncNilValues(t *testing.T) {
+	t.Parallel()
+
+	s := maps.NewSync[string, error]()
+	s.Set("a", nil)
+
+	if !s.Has("a") {
+		t.Errorf("expected a to be present")
+	}
+	if v, ok := s.Get("a"); !ok || v != nil {
+		t.Errorf("expected nil, true, but received %v, %t", v, ok)
+	}
+	if v, ok := s.Get("b"); ok || v != nil {
+		t.Errorf("expected nil, false, but received %v, %t", v, ok)
+	}
+	if v, ok := s.GetOrInsert("a", errors.New("x")); !ok || v != nil {
+		t.Errorf("expected nil, true, but received %v, %t", v, ok)
+	}
+
+	cnt := 0
+	s.ForEach(func(k string, v error) {
+		if k != "a" || v != nil {
+			t.Errorf("expected a, nil, but received %s, %v", k, v)
+		}
+		cnt++
+	})
+	if cnt != 1 {
+		t.Errorf("expected 1 element, but received %d", cnt)
+	}
+	if snap := s.Snapshot(); len(snap) != 1 || snap["a"] != nil {
+		t.Errorf("unexpected snapshot %+v", snap)
+	}
+
+	if v, ok := s.GetAndDelete("a"); !ok || v != nil || s.Has("a") {
+		t.Errorf("expected a to be removed with value nil, but received %v, %t", v, ok)
+	}
+}
@@ -0,0 +1,202 @@
+// multisets provides generic convenience functions for working with
+// multisets, also known as bags. A multiset is represented as a map from
+// each element to the number of times it occurs, the same shape produced
+// by slices.Tally. Elements with a count of zero or less are treated as
+// absent, and are never included in the results of these functions.
+package multisets
+
+/* Constructors */
+
+// FromSlice creates a new multiset containing each element in s
+// as many times as it occurs in s.
+func FromSlice[T comparable](s []T) map[T]int {
+	result := make(map[T]int)
+	for _, ele := range s {
+		result[ele]++
+	}
+
+	return result
+}
+
+// FromTally creates a new multiset from a map of element counts,
+// such as one produced by slices.Tally. Elements with a count
+// of zero or less are left out.
+func FromTally[T comparable](m map[T]int) map[T]int {
+	result := make(map[T]int, len(m))
+	for ele, cnt := range m {
+		if cnt > 0 {
+			result[ele] = cnt
+		}
+	}
+
+	return result
+}
+
+// New creates a new multiset containing the provided elements.
+func New[T comparable](eles ...T) map[T]int {
+	return FromSlice(eles)
+}
+
+/* Operations */
+
+// Add creates a copy of m with cnt more occurrences of ele.
+func Add[T comparable](m map[T]int, ele T, cnt int) map[T]int {
+	result := FromTally(m)
+	if cnt > 0 {
+		result[ele] += cnt
+	}
+
+	return result
+}
+
+// Contains checks whether m contains at least cnt occurrences of ele.
+func Contains[T comparable](m map[T]int, ele T, cnt int) bool {
+	return Count(m, ele) >= cnt
+}
+
+// Count returns the number of occurrences of ele in m.
+func Count[T comparable](m map[T]int, ele T) int {
+	if cnt := m[ele]; cnt > 0 {
+		return cnt
+	}
+
+	return 0
+}
+
+// Difference returns the elements in a less those in b,
+// with the count of each element in b subtracted from its count in a.
+func Difference[T comparable](a, b map[T]int) map[T]int {
+	result := make(map[T]int)
+	for ele, cnt := range a {
+		if diff := cnt - Count(b, ele); diff > 0 {
+			result[ele] = diff
+		}
+	}
+
+	return result
+}
+
+// Distinct returns the number of distinct elements in m.
+func Distinct[T comparable](m map[T]int) int {
+	cnt := 0
+	for _, c := range m {
+		if c > 0 {
+			cnt++
+		}
+	}
+
+	return cnt
+}
+
+// Equals checks whether a and b contain
+// the same elements with the same counts.
+func Equals[T comparable](a, b map[T]int) bool {
+	return Includes(a, b) && Includes(b, a)
+}
+
+// Includes checks whether a contains every element in b
+// at least as many times as b does.
+func Includes[T comparable](a, b map[T]int) bool {
+	for ele, cnt := range b {
+		if cnt > 0 && Count(a, ele) < cnt {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Intersect returns the elements in every one of ms,
+// each with the smallest of its counts among them.
+func Intersect[T comparable](ms ...map[T]int) map[T]int {
+	result := make(map[T]int)
+	if len(ms) == 0 {
+		return result
+	}
+
+Outer:
+	for ele, cnt := range ms[0] {
+		for _, m := range ms[1:] {
+			if c := Count(m, ele); c < cnt {
+				cnt = c
+			}
+			if cnt <= 0 {
+				continue Outer
+			}
+		}
+		if cnt > 0 {
+			result[ele] = cnt
+		}
+	}
+
+	return result
+}
+
+// Remove creates a copy of m with up to cnt occurrences of ele removed.
+func Remove[T comparable](m map[T]int, ele T, cnt int) map[T]int {
+	result := FromTally(m)
+	if cnt > 0 {
+		result[ele] -= cnt
+		if result[ele] <= 0 {
+			delete(result, ele)
+		}
+	}
+
+	return result
+}
+
+// Size returns the total number of occurrences of all elements in m.
+func Size[T comparable](m map[T]int) int {
+	size := 0
+	for _, cnt := range m {
+		if cnt > 0 {
+			size += cnt
+		}
+	}
+
+	return size
+}
+
+// Sum returns the elements in any one of ms,
+// each with the sum of its counts among them.
+func Sum[T comparable](ms ...map[T]int) map[T]int {
+	result := make(map[T]int)
+	for _, m := range ms {
+		for ele, cnt := range m {
+			if cnt > 0 {
+				result[ele] += cnt
+			}
+		}
+	}
+
+	return result
+}
+
+// ToSlice returns a slice containing each element in m as many times
+// as it occurs in m. Occurrences of the same element are adjacent,
+// but the elements are otherwise in an arbitrary order.
+func ToSlice[T comparable](m map[T]int) []T {
+	result := make([]T, 0, Size(m))
+	for ele, cnt := range m {
+		for i := 0; i < cnt; i++ {
+			result = append(result, ele)
+		}
+	}
+
+	return result
+}
+
+// Union returns the elements in any one of ms,
+// each with the largest of its counts among them.
+func Union[T comparable](ms ...map[T]int) map[T]int {
+	result := make(map[T]int)
+	for _, m := range ms {
+		for ele, cnt := range m {
+			if cnt > result[ele] {
+				result[ele] = cnt
+			}
+		}
+	}
+
+	return result
+}
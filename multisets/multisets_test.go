@@ -0,0 +1,231 @@
+package multisets_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcmathja/funky/multisets"
+)
+
+func TestFromTally(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  map[string]int
+		out map[string]int
+	}{
+		"simple case": {
+			in:  map[string]int{"a": 1, "b": 2},
+			out: map[string]int{"a": 1, "b": 2},
+		},
+		"non-positive counts": {
+			in:  map[string]int{"a": 1, "b": 0, "c": -2},
+			out: map[string]int{"a": 1},
+		},
+		"empty input": {
+			in:  map[string]int{},
+			out: map[string]int{},
+		},
+		"nil input": {
+			in:  nil,
+			out: map[string]int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := multisets.FromTally(tc.in)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestAddAndRemove(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fn  func(map[string]int) map[string]int
+		in  map[string]int
+		out map[string]int
+	}{
+		"Add new element": {
+			fn:  func(m map[string]int) map[string]int { return multisets.Add(m, "c", 2) },
+			in:  multisets.New("a", "a", "b"),
+			out: map[string]int{"a": 2, "b": 1, "c": 2},
+		},
+		"Add existing element": {
+			fn:  func(m map[string]int) map[string]int { return multisets.Add(m, "a", 1) },
+			in:  multisets.New("a", "a", "b"),
+			out: map[string]int{"a": 3, "b": 1},
+		},
+		"Add non-positive count": {
+			fn:  func(m map[string]int) map[string]int { return multisets.Add(m, "a", -1) },
+			in:  multisets.New("a", "a", "b"),
+			out: map[string]int{"a": 2, "b": 1},
+		},
+		"Remove some occurrences": {
+			fn:  func(m map[string]int) map[string]int { return multisets.Remove(m, "a", 1) },
+			in:  multisets.New("a", "a", "b"),
+			out: map[string]int{"a": 1, "b": 1},
+		},
+		"Remove below zero": {
+			fn:  func(m map[string]int) map[string]int { return multisets.Remove(m, "a", 5) },
+			in:  multisets.New("a", "a", "b"),
+			out: map[string]int{"b": 1},
+		},
+		"Remove missing element": {
+			fn:  func(m map[string]int) map[string]int { return multisets.Remove(m, "z", 1) },
+			in:  multisets.New("a", "a", "b"),
+			out: map[string]int{"a": 2, "b": 1},
+		},
+		"Remove non-positive count": {
+			fn:  func(m map[string]int) map[string]int { return multisets.Remove(m, "a", -1) },
+			in:  multisets.New("a", "a", "b"),
+			out: map[string]int{"a": 2, "b": 1},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			before := multisets.FromTally(tc.in)
+			out := tc.fn(tc.in)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if !reflect.DeepEqual(tc.in, before) {
+				t.Errorf(`expected input %+v to be unchanged from %+v`, tc.in, before)
+			}
+		})
+	}
+}
+
+func TestAlgebra(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in         []map[string]int
+		union      map[string]int
+		sum        map[string]int
+		intersect  map[string]int
+		difference map[string]int
+	}{
+		"simple case": {
+			in:         []map[string]int{{"a": 2, "b": 1}, {"a": 1, "b": 3, "c": 1}},
+			union:      map[string]int{"a": 2, "b": 3, "c": 1},
+			sum:        map[string]int{"a": 3, "b": 4, "c": 1},
+			intersect:  map[string]int{"a": 1, "b": 1},
+			difference: map[string]int{"a": 1},
+		},
+		"element missing from a later input": {
+			in:         []map[string]int{{"a": 2, "b": 1}, {"a": 1}, {"a": 3, "b": 5}},
+			union:      map[string]int{"a": 3, "b": 5},
+			sum:        map[string]int{"a": 6, "b": 6},
+			intersect:  map[string]int{"a": 1},
+			difference: map[string]int{"a": 1, "b": 1},
+		},
+		"non-positive counts": {
+			in:         []map[string]int{{"a": 2, "b": -1, "c": 0}, {"a": -3, "b": 2, "c": 0}},
+			union:      map[string]int{"a": 2, "b": 2},
+			sum:        map[string]int{"a": 2, "b": 2},
+			intersect:  map[string]int{},
+			difference: map[string]int{"a": 2},
+		},
+		"single input": {
+			in:         []map[string]int{{"a": 2}, {}},
+			union:      map[string]int{"a": 2},
+			sum:        map[string]int{"a": 2},
+			intersect:  map[string]int{},
+			difference: map[string]int{"a": 2},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out := multisets.Union(tc.in...); !reflect.DeepEqual(out, tc.union) {
+				t.Errorf(`expected union %+v to equal %+v`, out, tc.union)
+			}
+			if out := multisets.Sum(tc.in...); !reflect.DeepEqual(out, tc.sum) {
+				t.Errorf(`expected sum %+v to equal %+v`, out, tc.sum)
+			}
+			if out := multisets.Intersect(tc.in...); !reflect.DeepEqual(out, tc.intersect) {
+				t.Errorf(`expected intersection %+v to equal %+v`, out, tc.intersect)
+			}
+			if out := multisets.Difference(tc.in[0], tc.in[1]); !reflect.DeepEqual(out, tc.difference) {
+				t.Errorf(`expected difference %+v to equal %+v`, out, tc.difference)
+			}
+		})
+	}
+}
+
+func TestNoInputs(t *testing.T) {
+	t.Parallel()
+
+	if out := multisets.Union[string](); len(out) != 0 {
+		t.Errorf("expected an empty union, but received %+v", out)
+	}
+	if out := multisets.Sum[string](); len(out) != 0 {
+		t.Errorf("expected an empty sum, but received %+v", out)
+	}
+	if out := multisets.Intersect[string](); len(out) != 0 {
+		t.Errorf("expected an empty intersection, but received %+v", out)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	t.Parallel()
+
+	m := map[string]int{"a": 2, "b": 1, "c": 0, "d": -1}
+
+	testCases := map[string]struct {
+		ele      string
+		cnt      int
+		count    int
+		contains bool
+	}{
+		"enough occurrences":     {ele: "a", cnt: 2, count: 2, contains: true},
+		"not enough occurrences": {ele: "a", cnt: 3, count: 2, contains: false},
+		"zero count":             {ele: "c", cnt: 1, count: 0, contains: false},
+		"negative count":         {ele: "d", cnt: 1, count: 0, contains: false},
+		"missing element":        {ele: "z", cnt: 1, count: 0, contains: false},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if count := multisets.Count(m, tc.ele); count != tc.count {
+				t.Errorf("expected %d, but received %d", tc.count, count)
+			}
+			if contains := multisets.Contains(m, tc.ele, tc.cnt); contains != tc.contains {
+				t.Errorf("expected %t, but received %t", tc.contains, contains)
+			}
+		})
+	}
+
+	if size := multisets.Size(m); size != 3 {
+		t.Errorf("expected a size of 3, but received %d", size)
+	}
+	if distinct := multisets.Distinct(m); distinct != 2 {
+		t.Errorf("expected 2 distinct elements, but received %d", distinct)
+	}
+	if !multisets.Equals(m, multisets.New("b", "a", "a")) {
+		t.Errorf("expected %+v to equal its positive counts", m)
+	}
+	if slice := multisets.ToSlice(multisets.FromTally(m)); len(slice) != 3 {
+		t.Errorf("expected 3 elements, but received %+v", slice)
+	}
+}
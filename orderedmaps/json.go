@@ -0,0 +1,93 @@
+package orderedmaps
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/mcmathja/funky/pairs"
+)
+
+// MarshalJSON returns the JSON encoding of m as an object
+// whose keys appear in insertion order. Keys and values
+// are otherwise encoded exactly as json.Marshal would for a map.
+func (m *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	var err error
+	first := true
+	m.each(func(kv pairs.Pair[K, V]) bool {
+		// Encoding each entry as a single-entry map
+		// reuses encoding/json's rules for encoding keys.
+		var entry []byte
+		entry, err = json.Marshal(map[K]V{kv.Left: kv.Right})
+		if err != nil {
+			return false
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		buf.Write(entry[1 : len(entry)-1])
+		first = false
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON replaces the contents of m with the key value pairs
+// of the JSON object in data, in the order they appear.
+// If the same key is repeated twice, the last value wins,
+// but the key keeps the place of its first occurrence.
+// A JSON null leaves m unchanged.
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('{') {
+		return errors.New("expected JSON object")
+	}
+
+	result := &OrderedMap[K, V]{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+
+		// Decoding each entry as a single-entry map
+		// reuses encoding/json's rules for decoding keys.
+		entry := map[K]V{}
+		obj := append(append(append(append([]byte{'{'}, key...), ':'), value...), '}')
+		if err := json.Unmarshal(obj, &entry); err != nil {
+			return err
+		}
+		for k, v := range entry {
+			result.Set(k, v)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	*m = *result
+	return nil
+}
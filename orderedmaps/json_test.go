@@ -0,0 +1,147 @@
+package orderedmaps_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mcmathja/funky/orderedmaps"
+	"github.com/mcmathja/funky/pairs"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  *orderedmaps.OrderedMap[int, string]
+		out string
+	}{
+		"insertion order": {
+			in:  orderedmaps.New(pairs.New(10, "a"), pairs.New(9, "b"), pairs.New(100, "c")),
+			out: `{"10":"a","9":"b","100":"c"}`,
+		},
+		"empty map": {
+			in:  orderedmaps.New[int, string](),
+			out: `{}`,
+		},
+		"zero value": {
+			in:  &orderedmaps.OrderedMap[int, string]{},
+			out: `{}`,
+		},
+		"nil map": {
+			in:  nil,
+			out: `null`,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := json.Marshal(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != tc.out {
+				t.Errorf("expected %s to equal %s", out, tc.out)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in    string
+		init  []pairs.Pair[int, []int]
+		pairs []pairs.Pair[int, []int]
+		err   bool
+	}{
+		"insertion order": {
+			in:    `{"3":[1],"1":[2,3],"2":null}`,
+			pairs: []pairs.Pair[int, []int]{pairs.New(3, []int{1}), pairs.New(1, []int{2, 3}), pairs.New(2, []int(nil))},
+		},
+		"duplicate keys": {
+			in:    `{"3":[1],"1":[2],"3":[4]}`,
+			pairs: []pairs.Pair[int, []int]{pairs.New(3, []int{4}), pairs.New(1, []int{2})},
+		},
+		"replaces existing contents": {
+			in:    `{"1":[1]}`,
+			init:  []pairs.Pair[int, []int]{pairs.New(2, []int{2})},
+			pairs: []pairs.Pair[int, []int]{pairs.New(1, []int{1})},
+		},
+		"null leaves existing contents": {
+			in:    `null`,
+			init:  []pairs.Pair[int, []int]{pairs.New(2, []int{2})},
+			pairs: []pairs.Pair[int, []int]{pairs.New(2, []int{2})},
+		},
+		"empty object": {
+			in:    `{}`,
+			pairs: []pairs.Pair[int, []int]{},
+		},
+		"not an object": {
+			in:  `[1]`,
+			err: true,
+		},
+		"invalid key": {
+			in:  `{"x":[1]}`,
+			err: true,
+		},
+		"invalid value": {
+			in:  `{"1":"x"}`,
+			err: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := orderedmaps.New(tc.init...)
+			err := json.Unmarshal([]byte(tc.in), m)
+
+			if tc.err {
+				if err == nil {
+					t.Errorf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out := m.ToSlice(); !reflect.DeepEqual(out, tc.pairs) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.pairs)
+			}
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	in := struct {
+		Fields *orderedmaps.OrderedMap[string, int] `json:"fields"`
+	}{
+		Fields: orderedmaps.New(pairs.New("z", 1), pairs.New("a", 2), pairs.New("m", 3)),
+	}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"fields":{"z":1,"a":2,"m":3}}` {
+		t.Errorf(`expected %s to equal {"fields":{"z":1,"a":2,"m":3}}`, data)
+	}
+
+	out := in
+	out.Fields = nil
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Fields.ToSlice(), in.Fields.ToSlice()) {
+		t.Errorf(`expected %+v to equal %+v`, out.Fields.ToSlice(), in.Fields.ToSlice())
+	}
+}
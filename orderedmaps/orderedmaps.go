@@ -0,0 +1,306 @@
+// orderedmaps provides a map type that remembers the order in which
+// keys were inserted, along with generic convenience functions for
+// working with it. Unlike a plain map, operations that depend on order,
+// such as Take and TakeWhile, have well-defined results.
+package orderedmaps
+
+import (
+	"container/list"
+
+	"github.com/mcmathja/funky/pairs"
+)
+
+// OrderedMap is a map that remembers the order in which its keys were
+// first inserted. Setting the value of an existing key keeps its place.
+// The zero value is an empty OrderedMap ready to use.
+// An OrderedMap is not safe for concurrent use.
+type OrderedMap[K comparable, V any] struct {
+	// entries maps each key to its place in order.
+	entries map[K]*list.Element
+	// order holds the key value pairs in insertion order.
+	order *list.List
+}
+
+/* Constructors */
+
+// FromBatch creates a new OrderedMap containing the key value
+// pairs produced by b, in the order they are produced.
+// If the same key is repeated twice, the last value wins,
+// but the key keeps the place of its first occurrence.
+func FromBatch[K comparable, V any](b func(func(pairs.Pair[K, V]) bool)) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	b(func(kv pairs.Pair[K, V]) bool {
+		result.Set(kv.Left, kv.Right)
+		return true
+	})
+
+	return result
+}
+
+// FromChan creates a new OrderedMap containing the key value
+// pairs received on ch, in the order they are received.
+// It only returns its results once the channel closes.
+func FromChan[K comparable, V any](ch <-chan pairs.Pair[K, V]) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	for kv := range ch {
+		result.Set(kv.Left, kv.Right)
+	}
+
+	return result
+}
+
+// FromMap creates a new OrderedMap containing the key value pairs in m.
+// Since m has no order of its own, the order of the keys is arbitrary.
+func FromMap[K comparable, V any](m map[K]V) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	for k, v := range m {
+		result.Set(k, v)
+	}
+
+	return result
+}
+
+// FromSlice creates a new OrderedMap containing the key value pairs
+// in s, in order. If the same key is repeated twice, the last value
+// wins, but the key keeps the place of its first occurrence.
+func FromSlice[K comparable, V any](s []pairs.Pair[K, V]) *OrderedMap[K, V] {
+	return New(s...)
+}
+
+// New creates a new OrderedMap containing the provided key value pairs,
+// in order. If the same key is repeated twice, the last value wins,
+// but the key keeps the place of its first occurrence.
+func New[K comparable, V any](kvs ...pairs.Pair[K, V]) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	for _, kv := range kvs {
+		result.Set(kv.Left, kv.Right)
+	}
+
+	return result
+}
+
+/* Methods */
+
+// Delete removes k from m, reporting whether it was present.
+func (m *OrderedMap[K, V]) Delete(k K) bool {
+	elem, ok := m.entries[k]
+	if !ok {
+		return false
+	}
+
+	m.order.Remove(elem)
+	delete(m.entries, k)
+	return true
+}
+
+// ForEach calls fn on each key value pair in m, in order.
+func (m *OrderedMap[K, V]) ForEach(fn func(key K, value V)) {
+	m.each(func(kv pairs.Pair[K, V]) bool {
+		fn(kv.Left, kv.Right)
+		return true
+	})
+}
+
+// Get returns the value for k in m, reporting whether it was present.
+func (m *OrderedMap[K, V]) Get(k K) (V, bool) {
+	elem, ok := m.entries[k]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return elem.Value.(pairs.Pair[K, V]).Right, true
+}
+
+// Has reports whether k is present in m.
+func (m *OrderedMap[K, V]) Has(k K) bool {
+	_, ok := m.entries[k]
+	return ok
+}
+
+// Keys returns the keys in m, in order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	result := make([]K, 0, m.Len())
+	m.ForEach(func(k K, _ V) {
+		result = append(result, k)
+	})
+
+	return result
+}
+
+// Len returns the number of key value pairs in m.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.entries)
+}
+
+// Set makes k map to v in m. A new key is placed last,
+// while an existing key keeps its place.
+func (m *OrderedMap[K, V]) Set(k K, v V) {
+	if m.entries == nil {
+		m.entries = make(map[K]*list.Element)
+		m.order = list.New()
+	}
+
+	if elem, ok := m.entries[k]; ok {
+		elem.Value = pairs.New(k, v)
+		return
+	}
+
+	m.entries[k] = m.order.PushBack(pairs.New(k, v))
+}
+
+// ToBatch returns a batch producing the key value pairs in m, in order.
+// Its type matches batches.Batch, to which it can be converted.
+func (m *OrderedMap[K, V]) ToBatch() func(func(pairs.Pair[K, V]) bool) {
+	return m.each
+}
+
+// ToChan returns a channel that receives the key value pairs in m,
+// in order, from a goroutine that closes the channel once done.
+// The channel must be read until it closes, or the goroutine will leak.
+func (m *OrderedMap[K, V]) ToChan() <-chan pairs.Pair[K, V] {
+	result := make(chan pairs.Pair[K, V])
+	go func() {
+		defer close(result)
+		m.each(func(kv pairs.Pair[K, V]) bool {
+			result <- kv
+			return true
+		})
+	}()
+
+	return result
+}
+
+// ToMap returns a plain map containing the key value pairs in m.
+func (m *OrderedMap[K, V]) ToMap() map[K]V {
+	result := make(map[K]V, m.Len())
+	m.ForEach(func(k K, v V) {
+		result[k] = v
+	})
+
+	return result
+}
+
+// ToSlice returns the key value pairs in m, in order.
+func (m *OrderedMap[K, V]) ToSlice() []pairs.Pair[K, V] {
+	result := make([]pairs.Pair[K, V], 0, m.Len())
+	m.each(func(kv pairs.Pair[K, V]) bool {
+		result = append(result, kv)
+		return true
+	})
+
+	return result
+}
+
+// Values returns the values in m, in the order of their keys.
+func (m *OrderedMap[K, V]) Values() []V {
+	result := make([]V, 0, m.Len())
+	m.ForEach(func(_ K, v V) {
+		result = append(result, v)
+	})
+
+	return result
+}
+
+/* Operations */
+
+// Drop creates a new OrderedMap without the first num key value pairs in m.
+func Drop[K comparable, V any](m *OrderedMap[K, V], num int) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	m.ForEach(func(k K, v V) {
+		if num > 0 {
+			num--
+			return
+		}
+		result.Set(k, v)
+	})
+
+	return result
+}
+
+// DropWhile creates a new OrderedMap without the leading
+// key value pairs in m that satisfy the predicate fn.
+func DropWhile[K comparable, V any](m *OrderedMap[K, V], fn func(K, V) bool) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	dropping := true
+	m.ForEach(func(k K, v V) {
+		if dropping && fn(k, v) {
+			return
+		}
+		dropping = false
+		result.Set(k, v)
+	})
+
+	return result
+}
+
+// Filter creates a new OrderedMap containing the key value
+// pairs in m that satisfy the predicate fn, in order.
+func Filter[K comparable, V any](m *OrderedMap[K, V], fn func(K, V) bool) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	m.ForEach(func(k K, v V) {
+		if fn(k, v) {
+			result.Set(k, v)
+		}
+	})
+
+	return result
+}
+
+// Map creates a new OrderedMap containing the result of applying fn
+// to each key value pair in m, in order. If fn returns the same key
+// twice, the last value wins, but the key keeps its first place.
+func Map[K1, K2 comparable, V1, V2 any](m *OrderedMap[K1, V1], fn func(K1, V1) (K2, V2)) *OrderedMap[K2, V2] {
+	result := &OrderedMap[K2, V2]{}
+	m.ForEach(func(k K1, v V1) {
+		result.Set(fn(k, v))
+	})
+
+	return result
+}
+
+// Take creates a new OrderedMap containing
+// the first num key value pairs in m.
+func Take[K comparable, V any](m *OrderedMap[K, V], num int) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	m.each(func(kv pairs.Pair[K, V]) bool {
+		if result.Len() >= num {
+			return false
+		}
+		result.Set(kv.Left, kv.Right)
+		return true
+	})
+
+	return result
+}
+
+// TakeWhile creates a new OrderedMap containing the leading
+// key value pairs in m that satisfy the predicate fn.
+func TakeWhile[K comparable, V any](m *OrderedMap[K, V], fn func(K, V) bool) *OrderedMap[K, V] {
+	result := &OrderedMap[K, V]{}
+	m.each(func(kv pairs.Pair[K, V]) bool {
+		if !fn(kv.Left, kv.Right) {
+			return false
+		}
+		result.Set(kv.Left, kv.Right)
+		return true
+	})
+
+	return result
+}
+
+/* Helpers */
+
+// each calls fn on each key value pair in m, in order,
+// stopping early if fn returns false.
+func (m *OrderedMap[K, V]) each(fn func(pairs.Pair[K, V]) bool) {
+	if m.order == nil {
+		return
+	}
+
+	for elem := m.order.Front(); elem != nil; elem = elem.Next() {
+		if !fn(elem.Value.(pairs.Pair[K, V])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package orderedmaps_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcmathja/funky/orderedmaps"
+	"github.com/mcmathja/funky/pairs"
+)
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in     []pairs.Pair[string, int]
+		keys   []string
+		values []int
+	}{
+		"insertion order": {
+			in:     []pairs.Pair[string, int]{pairs.New("c", 1), pairs.New("a", 2), pairs.New("b", 3)},
+			keys:   []string{"c", "a", "b"},
+			values: []int{1, 2, 3},
+		},
+		"existing key keeps its place": {
+			in:     []pairs.Pair[string, int]{pairs.New("c", 1), pairs.New("a", 2), pairs.New("c", 3)},
+			keys:   []string{"c", "a"},
+			values: []int{3, 2},
+		},
+		"empty input": {
+			in:     []pairs.Pair[string, int]{},
+			keys:   []string{},
+			values: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := orderedmaps.FromSlice(tc.in)
+
+			if keys := m.Keys(); !reflect.DeepEqual(keys, tc.keys) {
+				t.Errorf(`expected %+v to equal %+v`, keys, tc.keys)
+			}
+			if values := m.Values(); !reflect.DeepEqual(values, tc.values) {
+				t.Errorf(`expected %+v to equal %+v`, values, tc.values)
+			}
+			if m.Len() != len(tc.keys) {
+				t.Errorf("expected length %d, but received %d", len(tc.keys), m.Len())
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		key     string
+		deleted bool
+		keys    []string
+	}{
+		"first key": {
+			key:     "a",
+			deleted: true,
+			keys:    []string{"b", "c"},
+		},
+		"middle key": {
+			key:     "b",
+			deleted: true,
+			keys:    []string{"a", "c"},
+		},
+		"last key": {
+			key:     "c",
+			deleted: true,
+			keys:    []string{"a", "b"},
+		},
+		"missing key": {
+			key:     "z",
+			deleted: false,
+			keys:    []string{"a", "b", "c"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := orderedmaps.New(pairs.New("a", 1), pairs.New("b", 2), pairs.New("c", 3))
+			deleted := m.Delete(tc.key)
+
+			if deleted != tc.deleted {
+				t.Errorf("expected %t, but received %t", tc.deleted, deleted)
+			}
+			if keys := m.Keys(); !reflect.DeepEqual(keys, tc.keys) {
+				t.Errorf(`expected %+v to equal %+v`, keys, tc.keys)
+			}
+			if m.Has(tc.key) {
+				t.Errorf("expected %s to be absent", tc.key)
+			}
+		})
+	}
+}
+
+func TestDeleteAndReinsert(t *testing.T) {
+	t.Parallel()
+
+	m := orderedmaps.New(pairs.New("a", 1), pairs.New("b", 2))
+	m.Delete("a")
+	m.Set("a", 3)
+
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+		t.Errorf(`expected %+v to equal %+v`, keys, []string{"b", "a"})
+	}
+	if v, ok := m.Get("a"); !ok || v != 3 {
+		t.Errorf("expected 3, true, but received %d, %t", v, ok)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	t.Parallel()
+
+	var m orderedmaps.OrderedMap[string, int]
+
+	if m.Len() != 0 || m.Has("a") || m.Delete("a") {
+		t.Errorf("expected the zero value to be empty")
+	}
+	if _, ok := m.Get("a"); ok {
+		t.Errorf("expected a to be absent")
+	}
+	if keys := m.Keys(); len(keys) != 0 {
+		t.Errorf("expected no keys, but received %+v", keys)
+	}
+	m.ForEach(func(k string, _ int) {
+		t.Errorf("expected no elements, but received %s", k)
+	})
+	for kv := range m.ToChan() {
+		t.Errorf("expected no elements, but received %+v", kv)
+	}
+
+	m.Set("b", 1)
+	m.Set("a", 2)
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+		t.Errorf(`expected %+v to equal %+v`, keys, []string{"b", "a"})
+	}
+}
+
+func TestOperations(t *testing.T) {
+	t.Parallel()
+
+	m := orderedmaps.New(pairs.New("d", 1), pairs.New("c", 2), pairs.New("b", 3), pairs.New("a", 4))
+	lt3 := func(_ string, v int) bool { return v < 3 }
+
+	testCases := map[string]struct {
+		out  *orderedmaps.OrderedMap[string, int]
+		keys []string
+	}{
+		"Take":          {out: orderedmaps.Take(m, 2), keys: []string{"d", "c"}},
+		"Take negative": {out: orderedmaps.Take(m, -1), keys: []string{}},
+		"Drop":          {out: orderedmaps.Drop(m, 3), keys: []string{"a"}},
+		"Drop too many": {out: orderedmaps.Drop(m, 9), keys: []string{}},
+		"TakeWhile":     {out: orderedmaps.TakeWhile(m, lt3), keys: []string{"d", "c"}},
+		"DropWhile":     {out: orderedmaps.DropWhile(m, lt3), keys: []string{"b", "a"}},
+		"Filter":        {out: orderedmaps.Filter(m, func(_ string, v int) bool { return v%2 == 0 }), keys: []string{"c", "a"}},
+		"FromBatch":     {out: orderedmaps.FromBatch(m.ToBatch()), keys: []string{"d", "c", "b", "a"}},
+		"FromChan":      {out: orderedmaps.FromChan(m.ToChan()), keys: []string{"d", "c", "b", "a"}},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if keys := tc.out.Keys(); !reflect.DeepEqual(keys, tc.keys) {
+				t.Errorf(`expected %+v to equal %+v`, keys, tc.keys)
+			}
+		})
+	}
+}
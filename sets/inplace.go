@@ -0,0 +1,86 @@
+package sets
+
+// The functions in this file modify the set they are given in place,
+// rather than returning a modified copy like the rest of the package.
+// They are intended for hot paths where copying large sets on every
+// operation dominates the cost. Each returns the modified set, which
+// is only different from dst if dst was nil, so like append, its
+// result should be used.
+
+// AddInto inserts the provided elements into dst.
+func AddInto[T comparable](dst map[T]struct{}, eles ...T) map[T]struct{} {
+	if dst == nil {
+		dst = make(map[T]struct{}, len(eles))
+	}
+	for _, ele := range eles {
+		dst[ele] = struct{}{}
+	}
+
+	return dst
+}
+
+// DifferenceFrom deletes the elements in any one of ss from dst.
+func DifferenceFrom[T comparable](dst map[T]struct{}, ss ...map[T]struct{}) map[T]struct{} {
+	if dst == nil {
+		return make(map[T]struct{})
+	}
+	for _, s := range ss {
+		if len(s) < len(dst) {
+			for ele := range s {
+				delete(dst, ele)
+			}
+			continue
+		}
+		for ele := range dst {
+			if _, ok := s[ele]; ok {
+				delete(dst, ele)
+			}
+		}
+	}
+
+	return dst
+}
+
+// IntersectInto deletes the elements from dst
+// that are not in every one of ss.
+func IntersectInto[T comparable](dst map[T]struct{}, ss ...map[T]struct{}) map[T]struct{} {
+	if dst == nil {
+		return make(map[T]struct{})
+	}
+	for ele := range dst {
+		for _, s := range ss {
+			if _, ok := s[ele]; !ok {
+				delete(dst, ele)
+				break
+			}
+		}
+	}
+
+	return dst
+}
+
+// RemoveFrom deletes the provided elements from dst.
+func RemoveFrom[T comparable](dst map[T]struct{}, eles ...T) map[T]struct{} {
+	if dst == nil {
+		return make(map[T]struct{})
+	}
+	for _, ele := range eles {
+		delete(dst, ele)
+	}
+
+	return dst
+}
+
+// UnionInto inserts the elements in every one of ss into dst.
+func UnionInto[T comparable](dst map[T]struct{}, ss ...map[T]struct{}) map[T]struct{} {
+	if dst == nil {
+		dst = make(map[T]struct{})
+	}
+	for _, s := range ss {
+		for ele := range s {
+			dst[ele] = struct{}{}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,112 @@
+package sets
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// MarshalJSON returns the JSON encoding of s as an array, in an order
+// that depends only on its elements, so that the same set always produces
+// the same encoding. Elements are grouped by their reflect.Kind. Within a
+// group, elements of a string, integer or floating point kind appear
+// in ascending order, while any others appear in the lexical order
+// of their encoded form.
+func MarshalJSON[T comparable](s map[T]struct{}) ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	eles := make([]T, 0, len(s))
+	encoded := make([][]byte, 0, len(s))
+	for ele := range s {
+		enc, err := json.Marshal(ele)
+		if err != nil {
+			return nil, err
+		}
+		eles = append(eles, ele)
+		encoded = append(encoded, enc)
+	}
+
+	idxs := make([]int, len(eles))
+	for idx := range idxs {
+		idxs[idx] = idx
+	}
+	sort.Slice(idxs, func(i, j int) bool {
+		a, b := idxs[i], idxs[j]
+		va, vb := reflect.ValueOf(eles[a]), reflect.ValueOf(eles[b])
+		if va.Kind() != vb.Kind() {
+			return va.Kind() < vb.Kind()
+		}
+		if less, ok := lessBasic(va, vb); ok {
+			return less
+		}
+		return bytes.Compare(encoded[a], encoded[b]) < 0
+	})
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, idx := range idxs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(encoded[idx])
+	}
+	buf.WriteByte(']')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON creates a new set containing
+// the elements of the JSON array in data.
+func UnmarshalJSON[T comparable](data []byte) (map[T]struct{}, error) {
+	var eles []T
+	if err := json.Unmarshal(data, &eles); err != nil {
+		return nil, err
+	}
+	if eles == nil {
+		return nil, nil
+	}
+
+	return FromSlice(eles), nil
+}
+
+// MarshalJSON implements json.Marshaler for Set using MarshalJSON.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Set using UnmarshalJSON.
+// It replaces the contents of s with the elements in data.
+// A JSON null leaves s unchanged, as with other Go values.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	result, err := UnmarshalJSON[T](data)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+
+	*s = result
+	return nil
+}
+
+// lessBasic compares a and b, which must be of the same kind,
+// if that is a string, integer or floating point kind,
+// reporting whether it was able to.
+func lessBasic(a, b reflect.Value) (bool, bool) {
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float(), true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,85 @@
+package sets
+
+// Set is a named set type with methods for the most common operations.
+// Its underlying type is map[T]struct{}, so a Set can be passed directly
+// to any function in this package, and their results can be assigned
+// to a Set without conversion.
+//
+// Add and Remove modify the Set they are called on, so the Set must be
+// created with NewSet or make rather than left nil. All other methods
+// leave it unchanged and return a new Set where applicable.
+type Set[T comparable] map[T]struct{}
+
+// NewSet creates a new Set containing the provided elements.
+func NewSet[T comparable](eles ...T) Set[T] {
+	return New(eles...)
+}
+
+// Add inserts the provided elements into s.
+func (s Set[T]) Add(eles ...T) {
+	for _, ele := range eles {
+		s[ele] = struct{}{}
+	}
+}
+
+// Clone returns a copy of s.
+func (s Set[T]) Clone() Set[T] {
+	return Union(s)
+}
+
+// Contains checks whether ele is in s.
+func (s Set[T]) Contains(ele T) bool {
+	return Contains(s, ele)
+}
+
+// Difference returns the elements in s that are not in any one of others.
+func (s Set[T]) Difference(others ...map[T]struct{}) Set[T] {
+	return DifferenceAll(s, others...)
+}
+
+// Equals checks whether s and other contain the same elements.
+func (s Set[T]) Equals(other map[T]struct{}) bool {
+	return Equals(s, other)
+}
+
+// Filter returns the elements in s that satisfy fn.
+func (s Set[T]) Filter(fn func(T) bool) Set[T] {
+	return Filter(s, fn)
+}
+
+// ForEach performs fn on each element in s, in an arbitrary order.
+func (s Set[T]) ForEach(fn func(T)) {
+	ForEach(s, fn)
+}
+
+// Intersect returns the elements in s that are also in every one of others.
+func (s Set[T]) Intersect(others ...map[T]struct{}) Set[T] {
+	return Intersect(append([]map[T]struct{}{s}, others...)...)
+}
+
+// Len returns the number of elements in s.
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Remove deletes the provided elements from s.
+func (s Set[T]) Remove(eles ...T) {
+	for _, ele := range eles {
+		delete(s, ele)
+	}
+}
+
+// ToSlice returns the elements in s, in an arbitrary order.
+func (s Set[T]) ToSlice() []T {
+	result := make([]T, 0, len(s))
+	for ele := range s {
+		result = append(result, ele)
+	}
+
+	return result
+}
+
+// Union returns the elements that are in s or in any one of others.
+func (s Set[T]) Union(others ...map[T]struct{}) Set[T] {
+	return Union(append([]map[T]struct{}{s}, others...)...)
+}
@@ -112,13 +112,23 @@ func Count[Elem comparable](set map[Elem]struct{}, fn func(Elem) bool) int {
 	return cnt
 }
 
-// Difference returns the elements in s1 that are not in s2.
-func Difference[T comparable](s1, s2 map[T]struct{}) map[T]struct{} {
+// Difference returns the elements in s that are not in any one of others.
+func Difference[T comparable](s map[T]struct{}, others ...map[T]struct{}) map[T]struct{} {
+	return DifferenceAll(s, others...)
+}
+
+// DifferenceAll returns the elements in base that are not
+// in any one of subtrahends. It is equivalent to Difference.
+func DifferenceAll[T comparable](base map[T]struct{}, subtrahends ...map[T]struct{}) map[T]struct{} {
 	result := make(map[T]struct{})
-	for ele := range s1 {
-		if _, ok := s2[ele]; !ok {
-			result[ele] = struct{}{}
+Outer:
+	for ele := range base {
+		for _, s := range subtrahends {
+			if _, ok := s[ele]; ok {
+				continue Outer
+			}
 		}
+		result[ele] = struct{}{}
 	}
 
 	return result
@@ -256,6 +266,38 @@ Outer:
 	return result
 }
 
+// IsProperSubset checks whether every element in a is also in b,
+// and b contains at least one element that is not in a.
+func IsProperSubset[T comparable](a, b map[T]struct{}) bool {
+	return len(a) < len(b) && IsSubset(a, b)
+}
+
+// IsProperSuperset checks whether every element in b is also in a,
+// and a contains at least one element that is not in b.
+func IsProperSuperset[T comparable](a, b map[T]struct{}) bool {
+	return IsProperSubset(b, a)
+}
+
+// IsSubset checks whether every element in a is also in b.
+func IsSubset[T comparable](a, b map[T]struct{}) bool {
+	if len(a) > len(b) {
+		return false
+	}
+
+	for ele := range a {
+		if _, ok := b[ele]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsSuperset checks whether every element in b is also in a.
+func IsSuperset[T comparable](a, b map[T]struct{}) bool {
+	return IsSubset(b, a)
+}
+
 // Map creates a new set where every element in s
 // has been mapped to a new element using fn.
 func Map[T, U comparable](s map[T]struct{}, fn func(T) U) map[U]struct{} {
@@ -1,6 +1,7 @@
 package sets_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/mcmathja/funky/sets"
@@ -152,3 +153,322 @@ func TestAny(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSubset(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		a              map[int]struct{}
+		b              map[int]struct{}
+		subset         bool
+		properSubset   bool
+		superset       bool
+		properSuperset bool
+	}{
+		"proper subset": {
+			a:            sets.New(1, 2),
+			b:            sets.New(1, 2, 3),
+			subset:       true,
+			properSubset: true,
+		},
+		"proper superset": {
+			a:              sets.New(1, 2, 3),
+			b:              sets.New(1, 2),
+			superset:       true,
+			properSuperset: true,
+		},
+		"equal sets": {
+			a:        sets.New(1, 2, 3),
+			b:        sets.New(1, 2, 3),
+			subset:   true,
+			superset: true,
+		},
+		"disjoint sets": {
+			a: sets.New(1, 2),
+			b: sets.New(3, 4),
+		},
+		"overlapping sets": {
+			a: sets.New(1, 2, 3),
+			b: sets.New(3, 4, 5),
+		},
+		"empty first set": {
+			a:            sets.New[int](),
+			b:            sets.New(1),
+			subset:       true,
+			properSubset: true,
+		},
+		"empty sets": {
+			a:        sets.New[int](),
+			b:        sets.New[int](),
+			subset:   true,
+			superset: true,
+		},
+		"nil sets": {
+			a:        nil,
+			b:        nil,
+			subset:   true,
+			superset: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out := sets.IsSubset(tc.a, tc.b); out != tc.subset {
+				t.Errorf("expected IsSubset to be %t, but received %t", tc.subset, out)
+			}
+			if out := sets.IsProperSubset(tc.a, tc.b); out != tc.properSubset {
+				t.Errorf("expected IsProperSubset to be %t, but received %t", tc.properSubset, out)
+			}
+			if out := sets.IsSuperset(tc.a, tc.b); out != tc.superset {
+				t.Errorf("expected IsSuperset to be %t, but received %t", tc.superset, out)
+			}
+			if out := sets.IsProperSuperset(tc.a, tc.b); out != tc.properSuperset {
+				t.Errorf("expected IsProperSuperset to be %t, but received %t", tc.properSuperset, out)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	s := sets.NewSet(1, 2, 3)
+	s.Add(4, 5)
+	s.Remove(1, 6)
+
+	if !s.Equals(sets.New(2, 3, 4, 5)) {
+		t.Errorf("expected %+v to equal %+v", s, sets.New(2, 3, 4, 5))
+	}
+	if !s.Contains(2) || s.Contains(1) {
+		t.Errorf("expected %+v to contain 2 but not 1", s)
+	}
+	if s.Len() != 4 || len(s.ToSlice()) != 4 {
+		t.Errorf("expected %+v to have 4 elements", s)
+	}
+
+	union := s.Union(sets.New(5, 6), sets.NewSet(7))
+	if !sets.Equals(union, sets.New(2, 3, 4, 5, 6, 7)) {
+		t.Errorf("expected %+v to equal %+v", union, sets.New(2, 3, 4, 5, 6, 7))
+	}
+
+	intersect := s.Intersect(sets.New(3, 4, 9))
+	if !intersect.Equals(sets.New(3, 4)) {
+		t.Errorf("expected %+v to equal %+v", intersect, sets.New(3, 4))
+	}
+
+	var filtered sets.Set[int] = sets.Filter(s, func(i int) bool { return i%2 == 0 })
+	if !filtered.Equals(s.Filter(func(i int) bool { return i%2 == 0 })) {
+		t.Errorf("expected %+v to equal %+v", filtered, sets.New(2, 4))
+	}
+
+	clone := s.Clone()
+	clone.Add(10)
+	if s.Contains(10) {
+		t.Errorf("expected %+v not to be modified by its clone", s)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  map[int]struct{}
+		out string
+	}{
+		"simple case": {
+			in:  sets.New(3, 1, 2),
+			out: `[1,2,3]`,
+		},
+		"numeric order": {
+			in:  sets.New(10, 9, -1, 100),
+			out: `[-1,9,10,100]`,
+		},
+		"empty input": {
+			in:  sets.New[int](),
+			out: `[]`,
+		},
+		"nil input": {
+			in:  nil,
+			out: `null`,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := sets.MarshalJSON(tc.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != tc.out {
+				t.Errorf("expected %s to equal %s", out, tc.out)
+			}
+
+			in, err := sets.UnmarshalJSON[int](out)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !sets.Equals(in, tc.in) {
+				t.Errorf("expected %+v to equal %+v", in, tc.in)
+			}
+		})
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	t.Parallel()
+
+	in := struct {
+		Tags sets.Set[string] `json:"tags"`
+	}{
+		Tags: sets.NewSet("b", "c", "a"),
+	}
+
+	out, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(out) != `{"tags":["a","b","c"]}` {
+		t.Errorf(`expected %s to equal {"tags":["a","b","c"]}`, out)
+	}
+
+	in.Tags = nil
+	if err := json.Unmarshal(out, &in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !in.Tags.Equals(sets.New("a", "b", "c")) {
+		t.Errorf("expected %+v to equal %+v", in.Tags, sets.New("a", "b", "c"))
+	}
+
+	if err := json.Unmarshal([]byte(`{"tags":null}`), &in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !in.Tags.Equals(sets.New("a", "b", "c")) {
+		t.Errorf("expected %+v to be left unchanged by null", in.Tags)
+	}
+
+	if err := json.Unmarshal([]byte(`{"tags":{"a":{}}}`), &in); err == nil {
+		t.Errorf("expected an error for a non-array input")
+	}
+}
+
+func TestDifferenceAll(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		base        map[int]struct{}
+		subtrahends []map[int]struct{}
+		out         map[int]struct{}
+	}{
+		"simple case": {
+			base:        sets.New(1, 2, 3, 4, 5),
+			subtrahends: []map[int]struct{}{sets.New(1, 2), sets.New(4, 6)},
+			out:         sets.New(3, 5),
+		},
+		"single subtrahend": {
+			base:        sets.New(1, 2, 3),
+			subtrahends: []map[int]struct{}{sets.New(2)},
+			out:         sets.New(1, 3),
+		},
+		"no subtrahends": {
+			base:        sets.New(1, 2, 3),
+			subtrahends: nil,
+			out:         sets.New(1, 2, 3),
+		},
+		"everything removed": {
+			base:        sets.New(1, 2),
+			subtrahends: []map[int]struct{}{sets.New(1), sets.New(2)},
+			out:         sets.New[int](),
+		},
+		"nil base": {
+			base:        nil,
+			subtrahends: []map[int]struct{}{sets.New(1)},
+			out:         sets.New[int](),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := sets.DifferenceAll(tc.base, tc.subtrahends...)
+
+			if !sets.Equals(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+
+			out = sets.Difference(tc.base, tc.subtrahends...)
+
+			if !sets.Equals(out, tc.out) {
+				t.Errorf(`expected Difference %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestInPlace(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fn  func(map[int]struct{}) map[int]struct{}
+		in  map[int]struct{}
+		out map[int]struct{}
+	}{
+		"AddInto": {
+			fn:  func(s map[int]struct{}) map[int]struct{} { return sets.AddInto(s, 3, 4) },
+			in:  sets.New(1, 2, 3),
+			out: sets.New(1, 2, 3, 4),
+		},
+		"AddInto nil input": {
+			fn:  func(s map[int]struct{}) map[int]struct{} { return sets.AddInto(s, 3, 4) },
+			in:  nil,
+			out: sets.New(3, 4),
+		},
+		"RemoveFrom": {
+			fn:  func(s map[int]struct{}) map[int]struct{} { return sets.RemoveFrom(s, 3, 4) },
+			in:  sets.New(1, 2, 3),
+			out: sets.New(1, 2),
+		},
+		"UnionInto": {
+			fn:  func(s map[int]struct{}) map[int]struct{} { return sets.UnionInto(s, sets.New(3, 4), sets.New(5)) },
+			in:  sets.New(1, 2, 3),
+			out: sets.New(1, 2, 3, 4, 5),
+		},
+		"IntersectInto": {
+			fn: func(s map[int]struct{}) map[int]struct{} {
+				return sets.IntersectInto(s, sets.New(2, 3, 4), sets.New(3, 2))
+			},
+			in:  sets.New(1, 2, 3),
+			out: sets.New(2, 3),
+		},
+		"DifferenceFrom": {
+			fn: func(s map[int]struct{}) map[int]struct{} {
+				return sets.DifferenceFrom(s, sets.New(1), sets.New(3, 4, 5, 6))
+			},
+			in:  sets.New(1, 2, 3),
+			out: sets.New(2),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := tc.fn(tc.in)
+
+			if !sets.Equals(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+			if tc.in != nil && !sets.Equals(tc.in, tc.out) {
+				t.Errorf(`expected input %+v to have been modified to %+v`, tc.in, tc.out)
+			}
+		})
+	}
+}
@@ -3,7 +3,9 @@ package slices
 
 import (
 	"errors"
+	"runtime"
 	"sort"
+	"sync"
 
 	"github.com/mcmathja/funky/constraints"
 	"github.com/mcmathja/funky/pairs"
@@ -87,6 +89,50 @@ func Cartesian[T, U any](s []T, ss []U) []pairs.Pair[T, U] {
 	return result
 }
 
+// CartesianN lazily generates the cartesian product of the slices in ss,
+// calling fn with each combination in turn until either every
+// combination has been produced or fn returns false.
+// The slice passed to fn is reused between calls,
+// so it must be copied if it is to be retained.
+func CartesianN[T any](fn func([]T) bool, ss ...[]T) {
+	for _, s := range ss {
+		if len(s) == 0 {
+			// The product with an empty slice is empty.
+			return
+		}
+	}
+
+	idxs := make([]int, len(ss))
+	curr := make([]T, len(ss))
+	for pos, s := range ss {
+		curr[pos] = s[0]
+	}
+
+	for {
+		if !fn(curr) {
+			return
+		}
+
+		// Advance the rightmost index, carrying leftward
+		// whenever an index wraps back around to zero.
+		pos := len(ss) - 1
+		for ; pos >= 0; pos-- {
+			idxs[pos]++
+			if idxs[pos] < len(ss[pos]) {
+				curr[pos] = ss[pos][idxs[pos]]
+				break
+			}
+
+			idxs[pos] = 0
+			curr[pos] = ss[pos][0]
+		}
+
+		if pos < 0 {
+			return
+		}
+	}
+}
+
 // ConsistsOf checks if s is made up of only elements
 // that are also present in eles, without regard
 // for arrangement or repetition.
@@ -549,6 +595,23 @@ func FromSet[K comparable](s map[K]struct{}) []K {
 	return result
 }
 
+// Generate produces a new slice of length n
+// where the element at each index is the result
+// of calling fn with that index.
+// If n is zero or negative, an empty slice is returned.
+func Generate[T any](n int, fn func(idx int) T) []T {
+	if n < 0 {
+		n = 0
+	}
+
+	result := make([]T, n)
+	for idx := range result {
+		result[idx] = fn(idx)
+	}
+
+	return result
+}
+
 // GroupBy groups elements by the result of a function call.
 func GroupBy[T any, U comparable](s []T, fn func(T) U) map[U][]T {
 	result := make(map[U][]T)
@@ -596,6 +659,31 @@ func LastIndexWhere[T any](s []T, fn func(T) bool) int {
 	return -1
 }
 
+// Linspace produces a new slice containing n evenly spaced values
+// between from and to, including both endpoints.
+// Each value is computed independently from its index,
+// so rounding error does not accumulate as it can with Range.
+// If n is zero or negative, an empty slice is returned.
+func Linspace(from, to float64, n int) []float64 {
+	if n <= 0 {
+		return make([]float64, 0)
+	}
+
+	result := make([]float64, n)
+	if n == 1 {
+		result[0] = from
+		return result
+	}
+
+	step := (to - from) / float64(n-1)
+	for idx := 0; idx < n-1; idx++ {
+		result[idx] = from + float64(idx)*step
+	}
+	result[n-1] = to
+
+	return result
+}
+
 // Map creates a new slice where every element in s
 // has been mapped to a new element using fn.
 func Map[T, U any](s []T, fn func(T) U) []U {
@@ -683,6 +771,34 @@ func NthIndexWhere[T any](s []T, n int, fn func(T) bool) int {
 	return -1
 }
 
+// NthSmallest returns the nth smallest element in s,
+// where n=1 is the minimum, n=2 is the second smallest, and so on.
+// It runs in linear time on average and does not modify s.
+// If n is out of range, it returns an error.
+// s must consist of primitives having a total order.
+func NthSmallest[T constraints.Ordered](s []T, n int) (T, error) {
+	return NthSmallestBy(s, n, func(a, b T) bool {
+		return a < b
+	})
+}
+
+// NthSmallestBy returns the nth smallest element in s
+// according to the provided less function,
+// where n=1 is the minimum, n=2 is the second smallest, and so on.
+// It runs in linear time on average and does not modify s.
+// If n is out of range, it returns an error.
+func NthSmallestBy[T any](s []T, n int, less func(a, b T) bool) (T, error) {
+	if n < 1 || n > len(s) {
+		var ele T
+		return ele, errors.New("no such element")
+	}
+
+	result := make([]T, len(s))
+	copy(result, s)
+
+	return introselect(result, n-1, less), nil
+}
+
 // Partition divides elements from s into two slices based on a predicate,
 // with passing elements in the first slice and failing elements in the second.
 func Partition[T any](s []T, fn func(T) bool) ([]T, []T) {
@@ -700,6 +816,68 @@ func Partition[T any](s []T, fn func(T) bool) ([]T, []T) {
 	return a, b
 }
 
+// parallelReduceArgs represent optional arguments to ParallelReduce.
+type parallelReduceArgs struct {
+	// workers indicates the maximum number of goroutines to use.
+	workers int
+}
+
+// ParallelReduceOpt represent optional arguments to ParallelReduce.
+type ParallelReduceOpt func(*parallelReduceArgs)
+
+// ParallelReduceWorkers is a ParallelReduceOpt that limits
+// the number of goroutines used to n. By default,
+// ParallelReduce uses runtime.GOMAXPROCS(0) workers.
+func ParallelReduceWorkers(n int) ParallelReduceOpt {
+	return func(args *parallelReduceArgs) {
+		args.workers = n
+	}
+}
+
+// ParallelReduce splits s into contiguous chunks and reduces
+// each chunk concurrently with fn, starting from identity,
+// before merging the partial accumulators in order with combine.
+// identity must be an identity element for combine, and combine
+// must be associative for the result to match Reduce.
+func ParallelReduce[T, U any](s []T, identity U, fn func(U, T) U, combine func(U, U) U, opts ...ParallelReduceOpt) U {
+	args := parallelReduceArgs{
+		workers: runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		opt(&args)
+	}
+
+	workers := args.workers
+	if workers > len(s) {
+		workers = len(s)
+	}
+	if workers <= 1 {
+		return Reduce(s, identity, fn)
+	}
+
+	chunk := (len(s) + workers - 1) / workers
+	workers = (len(s) + chunk - 1) / chunk
+	partials := make([]U, workers)
+
+	var wg sync.WaitGroup
+	for idx := range partials {
+		lo := idx * chunk
+		hi := lo + chunk
+		if hi > len(s) {
+			hi = len(s)
+		}
+
+		wg.Add(1)
+		go func(idx, lo, hi int) {
+			defer wg.Done()
+			partials[idx] = Reduce(s[lo:hi], identity, fn)
+		}(idx, lo, hi)
+	}
+	wg.Wait()
+
+	return Reduce(partials[1:], partials[0], combine)
+}
+
 func Permute[T any](s []T) [][]T {
 	// Set up the iteration state and the current permutation
 	// as the initial arrangement of elements.
@@ -783,6 +961,13 @@ func Range[T constraints.Real](from, to, step T) []T {
 	return result
 }
 
+// RangeN produces a new slice containing the values
+// from 0 (inclusive) to n (exclusive).
+// It is equivalent to Range(0, n, 1).
+func RangeN(n int) []int {
+	return Range(0, n, 1)
+}
+
 // Reduce applies fn to each element of s in turn
 // along with the value of an accumulator.
 // The accumulator is initialized with init.
@@ -809,6 +994,52 @@ func Repeat[T any](ele T, num int) []T {
 	return result
 }
 
+// ReplaceAll returns a copy of s where every occurrence
+// of old has been replaced with ele.
+func ReplaceAll[T comparable](s []T, old, ele T) []T {
+	return ReplaceWhere(s, func(e T) bool {
+		return e == old
+	}, ele)
+}
+
+// ReplaceFirst returns a copy of s where the first occurrence
+// of old has been replaced with ele. If old does not occur in s,
+// the copy is returned unchanged.
+func ReplaceFirst[T comparable](s []T, old, ele T) []T {
+	result := make([]T, len(s))
+	copy(result, s)
+
+	if idx := FirstIndexOf(s, old); idx >= 0 {
+		result[idx] = ele
+	}
+
+	return result
+}
+
+// ReplaceWhere returns a copy of s where every element
+// satisfying the predicate fn has been replaced with ele.
+func ReplaceWhere[T any](s []T, fn func(T) bool, ele T) []T {
+	return ReplaceWhereWith(s, fn, func(T) T {
+		return ele
+	})
+}
+
+// ReplaceWhereWith returns a copy of s where every element
+// satisfying the predicate fn has been replaced with
+// the result of applying transform to it.
+func ReplaceWhereWith[T any](s []T, fn func(T) bool, transform func(T) T) []T {
+	result := make([]T, len(s))
+	for idx, e := range s {
+		if fn(e) {
+			result[idx] = transform(e)
+		} else {
+			result[idx] = e
+		}
+	}
+
+	return result
+}
+
 // Reversed returns a copy of s with its elements reversed.
 func Reversed[T any](s []T) []T {
 	result := make([]T, len(s))
@@ -840,6 +1071,38 @@ func Rotate[T any](s []T, k int) []T {
 	return result
 }
 
+// RunLengthDecode expands a run-length encoded slice s
+// back into the original sequence of elements,
+// repeating each Left value Right times.
+// Runs with a count of zero or less are skipped.
+func RunLengthDecode[T any](s []pairs.Pair[T, int]) []T {
+	result := make([]T, 0)
+	for _, run := range s {
+		for cnt := 0; cnt < run.Right; cnt++ {
+			result = append(result, run.Left)
+		}
+	}
+
+	return result
+}
+
+// RunLengthEncode compresses s into a slice of runs,
+// where each run pairs an element with the number of times
+// it occurs consecutively at that position in s.
+func RunLengthEncode[T comparable](s []T) []pairs.Pair[T, int] {
+	result := make([]pairs.Pair[T, int], 0)
+	for _, ele := range s {
+		last := len(result) - 1
+		if last >= 0 && result[last].Left == ele {
+			result[last].Right++
+		} else {
+			result = append(result, pairs.New(ele, 1))
+		}
+	}
+
+	return result
+}
+
 // Size returns the size of s.
 func Size[T any](s []T) int {
 	return len(s)
@@ -1036,13 +1299,41 @@ func TallyBy[T any, U comparable](s []T, fn func(T) U) map[U]int {
 	return cnts
 }
 
+// transposeArgs represent optional arguments to Transpose.
+type transposeArgs[T any] struct {
+	// fill indicates whether short rows should be padded.
+	fill bool
+	// fillValue is the value used to pad short rows.
+	fillValue T
+}
+
+// TransposeOpt represent optional arguments to Transpose.
+type TransposeOpt[T any] func(*transposeArgs[T])
+
+// TransposeWithFill is a TransposeOpt that indicates
+// rows shorter than the longest row in s should be
+// padded with ele rather than causing an error.
+func TransposeWithFill[T any](ele T) TransposeOpt[T] {
+	return func(args *transposeArgs[T]) {
+		args.fill = true
+		args.fillValue = ele
+	}
+}
+
 // Transpose returns the transposition of s:
 // given s is a matrix of shape [m][n]T,
 // it returns a new matrix t of shape [n][m]T,
 // where t[j][i] = s[i][j] for 0 <= i < m and 0 <= j <= n.
-// If n is not consistent across subslices, it returns an error.
+// If n is not consistent across subslices, it returns an error,
+// unless TransposeWithFill is provided, in which case n is
+// the length of the longest subslice and missing elements
+// are filled with the given value.
+func Transpose[T any](s [][]T, opts ...TransposeOpt[T]) ([][]T, error) {
+	args := transposeArgs[T]{}
+	for _, opt := range opts {
+		opt(&args)
+	}
 
-func Transpose[T any](s [][]T) ([][]T, error) {
 	m := len(s)
 	if m == 0 {
 		return make([][]T, 0), nil
@@ -1050,22 +1341,54 @@ func Transpose[T any](s [][]T) ([][]T, error) {
 
 	n := len(s[0])
 	for i := 1; i < len(s); i++ {
-		if len(s[i]) != n {
+		if len(s[i]) == n {
+			continue
+		}
+
+		if !args.fill {
 			return nil, errors.New("all slices in s must have the same length")
 		}
+
+		if len(s[i]) > n {
+			n = len(s[i])
+		}
 	}
 
 	result := make([][]T, n)
 	for i := 0; i < n; i++ {
 		result[i] = make([]T, m)
 		for j := 0; j < m; j++ {
-			result[i][j] = s[j][i]
+			if i < len(s[j]) {
+				result[i][j] = s[j][i]
+			} else {
+				result[i][j] = args.fillValue
+			}
 		}
 	}
 
 	return result, nil
 }
 
+// Unfold produces a new slice by repeatedly applying fn to a state,
+// starting with seed. Each call to fn returns the next element,
+// the next state, and whether the element should be kept;
+// generation stops the first time fn returns false.
+func Unfold[T, U any](seed U, fn func(U) (T, U, bool)) []T {
+	result := make([]T, 0)
+	state := seed
+	for {
+		ele, next, ok := fn(state)
+		if !ok {
+			break
+		}
+
+		result = append(result, ele)
+		state = next
+	}
+
+	return result
+}
+
 // Updated returns a new slice with the item at index
 // replaced with the provided element.
 func Updated[T any](s []T, idx int, ele T) ([]T, error) {
@@ -1079,6 +1402,24 @@ func Updated[T any](s []T, idx int, ele T) ([]T, error) {
 	return ss, nil
 }
 
+// Without returns a copy of s with every occurrence
+// of the elements in eles removed.
+func Without[T comparable](s []T, eles ...T) []T {
+	excluded := make(map[T]struct{}, len(eles))
+	for _, ele := range eles {
+		excluded[ele] = struct{}{}
+	}
+
+	result := make([]T, 0, len(s))
+	for _, ele := range s {
+		if _, ok := excluded[ele]; !ok {
+			result = append(result, ele)
+		}
+	}
+
+	return result
+}
+
 // Zip matches up the elements at each index in s and ss
 // and returns the result as a "zipped up" slice of pairs.
 // For each pair in the resulting slice, the Left value
@@ -1204,3 +1545,63 @@ func apostolicoCrochemoreSearch[T comparable](s, seq []T) bool {
 
 	return false
 }
+
+// introselect finds the element that would be at index k
+// if s were sorted according to less, partially reordering s.
+// It performs quickselect with median-of-three pivots,
+// falling back to a full sort if partitioning degenerates.
+func introselect[T any](s []T, k int, less func(a, b T) bool) T {
+	lo, hi := 0, len(s)-1
+
+	// Allow roughly 2*log2(n) partitioning rounds before giving up.
+	depth := 0
+	for n := len(s); n > 0; n >>= 1 {
+		depth += 2
+	}
+
+	for lo < hi {
+		if depth == 0 {
+			window := s[lo : hi+1]
+			sort.Slice(window, func(i, j int) bool {
+				return less(window[i], window[j])
+			})
+			break
+		}
+		depth--
+
+		// Move the median of the first, middle, and last
+		// elements into the last position to act as the pivot.
+		mid := lo + (hi-lo)/2
+		if less(s[mid], s[lo]) {
+			s[mid], s[lo] = s[lo], s[mid]
+		}
+		if less(s[hi], s[lo]) {
+			s[hi], s[lo] = s[lo], s[hi]
+		}
+		if less(s[mid], s[hi]) {
+			s[mid], s[hi] = s[hi], s[mid]
+		}
+
+		// Partition around the pivot using the Lomuto scheme.
+		pivot := s[hi]
+		store := lo
+		for idx := lo; idx < hi; idx++ {
+			if less(s[idx], pivot) {
+				s[idx], s[store] = s[store], s[idx]
+				store++
+			}
+		}
+		s[store], s[hi] = s[hi], s[store]
+
+		switch {
+		case k == store:
+			return s[k]
+		case k < store:
+			hi = store - 1
+		default:
+			lo = store + 1
+		}
+	}
+
+	return s[k]
+}
@@ -449,6 +449,86 @@ func TestCartesian(t *testing.T) {
 	}
 }
 
+func TestCartesianN(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in    [][]int
+		limit int
+		out   [][]int
+	}{
+		"three dimensions": {
+			in: slices.New(
+				slices.New(1, 2),
+				slices.New(3),
+				slices.New(4, 5),
+			),
+			limit: -1,
+			out: slices.New(
+				slices.New(1, 3, 4),
+				slices.New(1, 3, 5),
+				slices.New(2, 3, 4),
+				slices.New(2, 3, 5),
+			),
+		},
+		"single dimension": {
+			in: slices.New(
+				slices.New(1, 2, 3),
+			),
+			limit: -1,
+			out: slices.New(
+				slices.New(1),
+				slices.New(2),
+				slices.New(3),
+			),
+		},
+		"early exit": {
+			in: slices.New(
+				slices.New(1, 2),
+				slices.New(3, 4),
+			),
+			limit: 3,
+			out: slices.New(
+				slices.New(1, 3),
+				slices.New(1, 4),
+				slices.New(2, 3),
+			),
+		},
+		"empty dimension": {
+			in: slices.New(
+				slices.New(1, 2),
+				slices.New[int](),
+			),
+			limit: -1,
+			out:   slices.New[[]int](),
+		},
+		"no dimensions": {
+			in:    slices.New[[]int](),
+			limit: -1,
+			out: slices.New(
+				slices.New[int](),
+			),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.New[[]int]()
+			slices.CartesianN(func(combo []int) bool {
+				out = append(out, slices.Take(combo, len(combo)))
+				return len(out) != tc.limit
+			}, tc.in...)
+
+			if !slices.Correspond(out, tc.out, slices.Equal[int]) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
 func TestConsistsOf(t *testing.T) {
 	t.Parallel()
 
@@ -2130,6 +2210,45 @@ func TestFromSet(t *testing.T) {
 	}
 }
 
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		n   int
+		fn  func(int) int
+		out []int
+	}{
+		"simple case": {
+			n:   4,
+			fn:  func(i int) int { return i * i },
+			out: slices.New(0, 1, 4, 9),
+		},
+		"n zero": {
+			n:   0,
+			fn:  func(i int) int { return i * i },
+			out: slices.New[int](),
+		},
+		"n negative": {
+			n:   -1,
+			fn:  func(i int) int { return i * i },
+			out: slices.New[int](),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.Generate(tc.n, tc.fn)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
 func TestGroupBy(t *testing.T) {
 	t.Parallel()
 
@@ -2339,6 +2458,73 @@ func TestLastIndexWhere(t *testing.T) {
 	}
 }
 
+func TestLinspace(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		from float64
+		to   float64
+		n    int
+		out  []float64
+	}{
+		"simple case": {
+			from: 0,
+			to:   1,
+			n:    5,
+			out:  slices.New(0, 0.25, 0.5, 0.75, 1),
+		},
+		"descending": {
+			from: 1,
+			to:   -1,
+			n:    3,
+			out:  slices.New[float64](1, 0, -1),
+		},
+		"inexact step": {
+			from: 0,
+			to:   1,
+			n:    11,
+			out:  slices.New(0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1),
+		},
+		"n one": {
+			from: 2,
+			to:   5,
+			n:    1,
+			out:  slices.New[float64](2),
+		},
+		"n zero": {
+			from: 2,
+			to:   5,
+			n:    0,
+			out:  slices.New[float64](),
+		},
+		"n negative": {
+			from: 2,
+			to:   5,
+			n:    -3,
+			out:  slices.New[float64](),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.Linspace(tc.from, tc.to, tc.n)
+
+			if !slices.Correspond(out, tc.out, func(a, b float64) bool {
+				return math.Abs(a-b) < 1e-9
+			}) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+
+			if len(out) > 0 && out[len(out)-1] != tc.out[len(tc.out)-1] {
+				t.Errorf(`expected last element %v to be exactly %v`, out[len(out)-1], tc.out[len(tc.out)-1])
+			}
+		})
+	}
+}
+
 func TestMap(t *testing.T) {
 	t.Parallel()
 
@@ -2660,6 +2846,106 @@ func TestNthIndexWhere(t *testing.T) {
 	}
 }
 
+func TestNthSmallest(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in    []int
+		n     int
+		out   int
+		error bool
+	}{
+		"minimum": {
+			in:  slices.New(5, 3, 9, 1, 7),
+			n:   1,
+			out: 1,
+		},
+		"maximum": {
+			in:  slices.New(5, 3, 9, 1, 7),
+			n:   5,
+			out: 9,
+		},
+		"median": {
+			in:  slices.New(5, 3, 9, 1, 7),
+			n:   3,
+			out: 5,
+		},
+		"duplicates": {
+			in:  slices.New(2, 2, 1, 2, 3, 2),
+			n:   5,
+			out: 2,
+		},
+		"already sorted": {
+			in:  slices.RangeN(100),
+			n:   42,
+			out: 41,
+		},
+		"all equal": {
+			in:  slices.Repeat(7, 50),
+			n:   25,
+			out: 7,
+		},
+		"n zero": {
+			in:    slices.New(1, 2, 3),
+			n:     0,
+			error: true,
+		},
+		"n too large": {
+			in:    slices.New(1, 2, 3),
+			n:     4,
+			error: true,
+		},
+		"empty input": {
+			in:    slices.New[int](),
+			n:     1,
+			error: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			orig := slices.Take(tc.in, len(tc.in))
+			out, err := slices.NthSmallest(tc.in, tc.n)
+
+			if tc.error && err == nil {
+				t.Errorf("should have errored, but did not")
+			}
+
+			if !tc.error && err != nil {
+				t.Errorf("should not have errored, but got %v", err)
+			}
+
+			if out != tc.out {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+
+			if !slices.Equal(tc.in, orig) {
+				t.Errorf(`expected input %v to be unmodified`, tc.in)
+			}
+		})
+	}
+}
+
+func TestNthSmallestBy(t *testing.T) {
+	t.Parallel()
+
+	in := slices.Reversed(slices.RangeN(1000))
+	less := func(a, b int) bool { return a > b }
+	for n := 1; n <= len(in); n++ {
+		out, err := slices.NthSmallestBy(in, n, less)
+		if err != nil {
+			t.Fatalf("should not have errored, but got %v", err)
+		}
+
+		if out != len(in)-n {
+			t.Fatalf(`expected %v to equal %v`, out, len(in)-n)
+		}
+	}
+}
+
 func TestPartition(t *testing.T) {
 	t.Parallel()
 
@@ -2713,6 +2999,71 @@ func TestPartition(t *testing.T) {
 	}
 }
 
+func TestParallelReduce(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in      []int
+		workers int
+		out     string
+	}{
+		"simple case": {
+			in:      slices.RangeN(10),
+			workers: 3,
+			out:     "0123456789",
+		},
+		"more workers than elements": {
+			in:      slices.RangeN(3),
+			workers: 8,
+			out:     "012",
+		},
+		"uneven chunks": {
+			in:      slices.RangeN(5),
+			workers: 4,
+			out:     "01234",
+		},
+		"uneven chunks with many workers": {
+			in:      slices.RangeN(10),
+			workers: 7,
+			out:     "0123456789",
+		},
+		"single worker": {
+			in:      slices.RangeN(5),
+			workers: 1,
+			out:     "01234",
+		},
+		"zero workers": {
+			in:      slices.RangeN(5),
+			workers: 0,
+			out:     "01234",
+		},
+		"empty input": {
+			in:      slices.New[int](),
+			workers: 4,
+			out:     "",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.ParallelReduce(
+				tc.in,
+				"",
+				func(acc string, i int) string { return acc + strconv.Itoa(i) },
+				func(a, b string) string { return a + b },
+				slices.ParallelReduceWorkers(tc.workers),
+			)
+
+			if out != tc.out {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
 func TestPermute(t *testing.T) {
 	t.Parallel()
 
@@ -3055,6 +3406,41 @@ func TestRange(t *testing.T) {
 	})
 }
 
+func TestRangeN(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		n   int
+		out []int
+	}{
+		"simple case": {
+			n:   4,
+			out: slices.New(0, 1, 2, 3),
+		},
+		"n zero": {
+			n:   0,
+			out: slices.New[int](),
+		},
+		"n negative": {
+			n:   -2,
+			out: slices.New[int](),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.RangeN(tc.n)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
 func TestReduce(t *testing.T) {
 	t.Parallel()
 
@@ -3148,24 +3534,44 @@ func TestRepeat(t *testing.T) {
 	}
 }
 
-func TestReversed(t *testing.T) {
+func TestReplaceAll(t *testing.T) {
 	t.Parallel()
 
 	testCases := map[string]struct {
 		in  []int
 		out []int
+		old int
+		ele int
 	}{
 		"simple case": {
 			in:  slices.New(1, 2, 3),
-			out: slices.New(3, 2, 1),
+			out: slices.New(1, 100, 3),
+			old: 2,
+			ele: 100,
 		},
-		"single element": {
-			in:  slices.New(3),
-			out: slices.New(3),
+		"multiple occurrences": {
+			in:  slices.New(2, 1, 2, 3, 2),
+			out: slices.New(100, 1, 100, 3, 100),
+			old: 2,
+			ele: 100,
+		},
+		"no occurrences": {
+			in:  slices.New(1, 2, 3),
+			out: slices.New(1, 2, 3),
+			old: 4,
+			ele: 100,
 		},
 		"empty input": {
 			in:  slices.New[int](),
 			out: slices.New[int](),
+			old: 2,
+			ele: 100,
+		},
+		"nil input": {
+			in:  nil,
+			out: slices.New[int](),
+			old: 2,
+			ele: 100,
 		},
 	}
 
@@ -3174,46 +3580,216 @@ func TestReversed(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			out := slices.Reversed(tc.in)
+			out := slices.ReplaceAll(tc.in, tc.old, tc.ele)
 
 			if !slices.Equal(out, tc.out) {
-				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
 			}
 		})
 	}
 }
 
-func TestRotate(t *testing.T) {
+func TestReplaceFirst(t *testing.T) {
 	t.Parallel()
 
 	testCases := map[string]struct {
 		in  []int
 		out []int
-		k   int
+		old int
+		ele int
 	}{
 		"simple case": {
-			in:  slices.New(1, 2, 3, 4, 5),
-			out: slices.New(4, 5, 1, 2, 3),
-			k:   2,
+			in:  slices.New(1, 2, 3),
+			out: slices.New(1, 100, 3),
+			old: 2,
+			ele: 100,
 		},
-		"k is zero": {
-			in:  slices.New(1, 2, 3, 4, 5),
-			out: slices.New(1, 2, 3, 4, 5),
-			k:   0,
+		"multiple occurrences": {
+			in:  slices.New(2, 1, 2, 3, 2),
+			out: slices.New(100, 1, 2, 3, 2),
+			old: 2,
+			ele: 100,
 		},
-		"k is n": {
-			in:  slices.New(1, 2, 3, 4, 5),
-			out: slices.New(1, 2, 3, 4, 5),
-			k:   5,
+		"no occurrences": {
+			in:  slices.New(1, 2, 3),
+			out: slices.New(1, 2, 3),
+			old: 4,
+			ele: 100,
 		},
-		"k is larger than n": {
-			in:  slices.New(1, 2, 3, 4, 5),
-			out: slices.New(5, 1, 2, 3, 4),
-			k:   6,
+		"empty input": {
+			in:  slices.New[int](),
+			out: slices.New[int](),
+			old: 2,
+			ele: 100,
 		},
-		"k is less than zero": {
-			in:  slices.New(1, 2, 3, 4, 5),
-			out: slices.New(3, 4, 5, 1, 2),
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.ReplaceFirst(tc.in, tc.old, tc.ele)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestReplaceWhere(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in   []int
+		out  []int
+		pred func(int) bool
+		ele  int
+	}{
+		"simple case": {
+			in:   slices.New(1, 2, 3, 4),
+			out:  slices.New(1, 0, 3, 0),
+			pred: func(i int) bool { return i%2 == 0 },
+			ele:  0,
+		},
+		"none match": {
+			in:   slices.New(1, 3, 5),
+			out:  slices.New(1, 3, 5),
+			pred: func(i int) bool { return i%2 == 0 },
+			ele:  0,
+		},
+		"empty input": {
+			in:   slices.New[int](),
+			out:  slices.New[int](),
+			pred: func(i int) bool { return i%2 == 0 },
+			ele:  0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.ReplaceWhere(tc.in, tc.pred, tc.ele)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestReplaceWhereWith(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in        []int
+		out       []int
+		pred      func(int) bool
+		transform func(int) int
+	}{
+		"simple case": {
+			in:        slices.New(1, 2, 3, 4),
+			out:       slices.New(1, 20, 3, 40),
+			pred:      func(i int) bool { return i%2 == 0 },
+			transform: func(i int) int { return i * 10 },
+		},
+		"none match": {
+			in:        slices.New(1, 3, 5),
+			out:       slices.New(1, 3, 5),
+			pred:      func(i int) bool { return i%2 == 0 },
+			transform: func(i int) int { return i * 10 },
+		},
+		"empty input": {
+			in:        slices.New[int](),
+			out:       slices.New[int](),
+			pred:      func(i int) bool { return i%2 == 0 },
+			transform: func(i int) int { return i * 10 },
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.ReplaceWhereWith(tc.in, tc.pred, tc.transform)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestReversed(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  []int
+		out []int
+	}{
+		"simple case": {
+			in:  slices.New(1, 2, 3),
+			out: slices.New(3, 2, 1),
+		},
+		"single element": {
+			in:  slices.New(3),
+			out: slices.New(3),
+		},
+		"empty input": {
+			in:  slices.New[int](),
+			out: slices.New[int](),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.Reversed(tc.in)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  []int
+		out []int
+		k   int
+	}{
+		"simple case": {
+			in:  slices.New(1, 2, 3, 4, 5),
+			out: slices.New(4, 5, 1, 2, 3),
+			k:   2,
+		},
+		"k is zero": {
+			in:  slices.New(1, 2, 3, 4, 5),
+			out: slices.New(1, 2, 3, 4, 5),
+			k:   0,
+		},
+		"k is n": {
+			in:  slices.New(1, 2, 3, 4, 5),
+			out: slices.New(1, 2, 3, 4, 5),
+			k:   5,
+		},
+		"k is larger than n": {
+			in:  slices.New(1, 2, 3, 4, 5),
+			out: slices.New(5, 1, 2, 3, 4),
+			k:   6,
+		},
+		"k is less than zero": {
+			in:  slices.New(1, 2, 3, 4, 5),
+			out: slices.New(3, 4, 5, 1, 2),
 			k:   -2,
 		},
 		"k is less than -n": {
@@ -3247,6 +3823,106 @@ func TestRotate(t *testing.T) {
 	}
 }
 
+func TestRunLengthDecode(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  []pairs.Pair[string, int]
+		out []string
+	}{
+		"simple case": {
+			in: slices.New(
+				pairs.New("a", 2),
+				pairs.New("b", 1),
+				pairs.New("a", 3),
+			),
+			out: slices.New("a", "a", "b", "a", "a", "a"),
+		},
+		"non-positive counts": {
+			in: slices.New(
+				pairs.New("a", 0),
+				pairs.New("b", -1),
+				pairs.New("c", 1),
+			),
+			out: slices.New("c"),
+		},
+		"empty input": {
+			in:  slices.New[pairs.Pair[string, int]](),
+			out: slices.New[string](),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.RunLengthDecode(tc.in)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestRunLengthEncode(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  []string
+		out []pairs.Pair[string, int]
+	}{
+		"simple case": {
+			in: slices.New("a", "a", "b", "a", "a", "a"),
+			out: slices.New(
+				pairs.New("a", 2),
+				pairs.New("b", 1),
+				pairs.New("a", 3),
+			),
+		},
+		"no repeats": {
+			in: slices.New("a", "b", "c"),
+			out: slices.New(
+				pairs.New("a", 1),
+				pairs.New("b", 1),
+				pairs.New("c", 1),
+			),
+		},
+		"single run": {
+			in: slices.New("a", "a", "a"),
+			out: slices.New(
+				pairs.New("a", 3),
+			),
+		},
+		"empty input": {
+			in:  slices.New[string](),
+			out: slices.New[pairs.Pair[string, int]](),
+		},
+		"nil input": {
+			in:  nil,
+			out: slices.New[pairs.Pair[string, int]](),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.RunLengthEncode(tc.in)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+
+			if decoded := slices.RunLengthDecode(out); !slices.Equal(decoded, tc.in) {
+				t.Errorf(`expected decoded %v to equal %v`, decoded, tc.in)
+			}
+		})
+	}
+}
+
 func TestSize(t *testing.T) {
 	t.Parallel()
 
@@ -3960,6 +4636,125 @@ func TestTranspose(t *testing.T) {
 	}
 }
 
+func TestTransposeWithFill(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in   [][]int
+		out  [][]int
+		fill int
+	}{
+		"consistent rows": {
+			in: slices.New(
+				slices.New(1, 2, 3),
+				slices.New(4, 5, 6),
+			),
+			out: slices.New(
+				slices.New(1, 4),
+				slices.New(2, 5),
+				slices.New(3, 6),
+			),
+			fill: -1,
+		},
+		"short middle row": {
+			in: slices.New(
+				slices.New(1, 2, 3),
+				slices.New(4, 6),
+				slices.New(7, 8, 9),
+			),
+			out: slices.New(
+				slices.New(1, 4, 7),
+				slices.New(2, 6, 8),
+				slices.New(3, -1, 9),
+			),
+			fill: -1,
+		},
+		"short first row": {
+			in: slices.New(
+				slices.New(1),
+				slices.New(2, 3, 4),
+			),
+			out: slices.New(
+				slices.New(1, 2),
+				slices.New(0, 3),
+				slices.New(0, 4),
+			),
+			fill: 0,
+		},
+		"empty row": {
+			in: slices.New(
+				slices.New(1, 2),
+				slices.New[int](),
+			),
+			out: slices.New(
+				slices.New(1, -1),
+				slices.New(2, -1),
+			),
+			fill: -1,
+		},
+		"input nil": {
+			in:   nil,
+			out:  slices.New[[]int](),
+			fill: -1,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := slices.Transpose(tc.in, slices.TransposeWithFill(tc.fill))
+
+			if err != nil {
+				t.Errorf("should not have errored, but got %v", err)
+			}
+
+			if !slices.Correspond(out, tc.out, slices.Equal[int]) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestUnfold(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		seed pairs.Pair[int, int]
+		fn   func(pairs.Pair[int, int]) (int, pairs.Pair[int, int], bool)
+		out  []int
+	}{
+		"fibonacci": {
+			seed: pairs.New(0, 1),
+			fn: func(p pairs.Pair[int, int]) (int, pairs.Pair[int, int], bool) {
+				return p.Left, pairs.New(p.Right, p.Left+p.Right), p.Left < 20
+			},
+			out: slices.New(0, 1, 1, 2, 3, 5, 8, 13),
+		},
+		"immediately done": {
+			seed: pairs.New(0, 1),
+			fn: func(p pairs.Pair[int, int]) (int, pairs.Pair[int, int], bool) {
+				return 0, p, false
+			},
+			out: slices.New[int](),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.Unfold(tc.seed, tc.fn)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
 func TestUpdated(t *testing.T) {
 	t.Parallel()
 
@@ -4035,6 +4830,70 @@ func TestUpdated(t *testing.T) {
 	}
 }
 
+func TestWithout(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in     []int
+		out    []int
+		values []int
+	}{
+		"simple case": {
+			in:     slices.New(1, 2, 3, 4),
+			out:    slices.New(1, 3, 4),
+			values: []int{2},
+		},
+		"multiple elements": {
+			in:     slices.New(1, 2, 3, 4),
+			out:    slices.New(1, 4),
+			values: []int{2, 3},
+		},
+		"multiple occurrences": {
+			in:     slices.New(2, 1, 2, 3, 2),
+			out:    slices.New(1, 3),
+			values: []int{2},
+		},
+		"values not present": {
+			in:     slices.New(1, 2, 3),
+			out:    slices.New(1, 2, 3),
+			values: []int{4, 5},
+		},
+		"all removed": {
+			in:     slices.New(1, 2, 1),
+			out:    slices.New[int](),
+			values: []int{1, 2},
+		},
+		"nil values": {
+			in:     slices.New(1, 2, 3),
+			out:    slices.New(1, 2, 3),
+			values: nil,
+		},
+		"empty input": {
+			in:     slices.New[int](),
+			out:    slices.New[int](),
+			values: []int{1},
+		},
+		"nil input": {
+			in:     nil,
+			out:    slices.New[int](),
+			values: []int{1},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := slices.Without(tc.in, tc.values...)
+
+			if !slices.Equal(out, tc.out) {
+				t.Errorf(`expected %v to equal %v`, out, tc.out)
+			}
+		})
+	}
+}
+
 func TestZip(t *testing.T) {
 	t.Parallel()
 
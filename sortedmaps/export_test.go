@@ -0,0 +1,43 @@
+package sortedmaps
+
+// Balanced checks whether the tree backing m satisfies the AVL
+// invariants: every node's stored height is correct, its subtrees
+// differ in height by at most one, its keys are in order, and the
+// number of nodes matches the recorded size.
+func Balanced[K, V any](m *SortedMap[K, V]) bool {
+	cnt := 0
+	var check func(n *node[K, V], lo, hi *K) bool
+	check = func(n *node[K, V], lo, hi *K) bool {
+		if n == nil {
+			return true
+		}
+		cnt++
+
+		if lo != nil && !m.less(*lo, n.key) {
+			return false
+		}
+		if hi != nil && !m.less(n.key, *hi) {
+			return false
+		}
+
+		l, r := height(n.left), height(n.right)
+		if l-r > 1 || r-l > 1 {
+			return false
+		}
+		if n.height != 1+max(l, r) {
+			return false
+		}
+
+		return check(n.left, lo, &n.key) && check(n.right, &n.key, hi)
+	}
+
+	return check(m.root, nil, nil) && cnt == m.size
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
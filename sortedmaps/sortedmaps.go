@@ -0,0 +1,444 @@
+// sortedmaps provides a map type that keeps its keys sorted, along
+// with generic convenience functions for working with it. It supports
+// ordered iteration and range queries that a plain map cannot express.
+package sortedmaps
+
+import (
+	"github.com/mcmathja/funky/constraints"
+	"github.com/mcmathja/funky/pairs"
+)
+
+// SortedMap is a map that keeps its keys sorted, backed by a balanced
+// binary search tree. Lookups, insertions and deletions take
+// logarithmic time. A SortedMap must be created with New or NewBy,
+// and is not safe for concurrent use.
+type SortedMap[K, V any] struct {
+	root *node[K, V]
+	size int
+	less func(a, b K) bool
+}
+
+/* Constructors */
+
+// FromMap creates a new SortedMap containing the key value pairs in m.
+func FromMap[K constraints.Ordered, V any](m map[K]V) *SortedMap[K, V] {
+	result := New[K, V]()
+	for k, v := range m {
+		result.Set(k, v)
+	}
+
+	return result
+}
+
+// New creates a new SortedMap containing the provided key value pairs,
+// with its keys sorted in ascending order. If the same key is repeated
+// twice, the last value wins.
+func New[K constraints.Ordered, V any](kvs ...pairs.Pair[K, V]) *SortedMap[K, V] {
+	return NewBy(func(a, b K) bool {
+		return a < b
+	}, kvs...)
+}
+
+// NewBy creates a new SortedMap containing the provided key value pairs,
+// with its keys sorted in the order determined by less. Keys for which
+// neither less(a, b) nor less(b, a) holds are treated as the same key.
+// If the same key is repeated twice, the last value wins.
+func NewBy[K, V any](less func(a, b K) bool, kvs ...pairs.Pair[K, V]) *SortedMap[K, V] {
+	result := &SortedMap[K, V]{
+		less: less,
+	}
+	for _, kv := range kvs {
+		result.Set(kv.Left, kv.Right)
+	}
+
+	return result
+}
+
+/* Methods */
+
+// Between returns the key value pairs in m with keys
+// between from (inclusive) and to (exclusive), in order.
+func (m *SortedMap[K, V]) Between(from, to K) []pairs.Pair[K, V] {
+	result := make([]pairs.Pair[K, V], 0)
+	m.between(m.root, from, to, &result)
+
+	return result
+}
+
+// Ceiling returns the key value pair in m with the
+// smallest key greater than or equal to k, if any.
+func (m *SortedMap[K, V]) Ceiling(k K) (pairs.Pair[K, V], bool) {
+	var best *node[K, V]
+	for n := m.root; n != nil; {
+		if m.less(n.key, k) {
+			n = n.right
+		} else {
+			best = n
+			n = n.left
+		}
+	}
+
+	return best.pair()
+}
+
+// Delete removes k from m, reporting whether it was present.
+func (m *SortedMap[K, V]) Delete(k K) bool {
+	var removed bool
+	m.root, removed = m.remove(m.root, k)
+	if removed {
+		m.size--
+	}
+
+	return removed
+}
+
+// Floor returns the key value pair in m with the
+// largest key less than or equal to k, if any.
+func (m *SortedMap[K, V]) Floor(k K) (pairs.Pair[K, V], bool) {
+	var best *node[K, V]
+	for n := m.root; n != nil; {
+		if m.less(k, n.key) {
+			n = n.left
+		} else {
+			best = n
+			n = n.right
+		}
+	}
+
+	return best.pair()
+}
+
+// ForEach calls fn on each key value pair in m, in order.
+func (m *SortedMap[K, V]) ForEach(fn func(key K, value V)) {
+	m.each(func(kv pairs.Pair[K, V]) bool {
+		fn(kv.Left, kv.Right)
+		return true
+	})
+}
+
+// Get returns the value for k in m, reporting whether it was present.
+func (m *SortedMap[K, V]) Get(k K) (V, bool) {
+	if n := m.find(k); n != nil {
+		return n.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Has reports whether k is present in m.
+func (m *SortedMap[K, V]) Has(k K) bool {
+	return m.find(k) != nil
+}
+
+// Keys returns the keys in m, in order.
+func (m *SortedMap[K, V]) Keys() []K {
+	result := make([]K, 0, m.size)
+	m.ForEach(func(k K, _ V) {
+		result = append(result, k)
+	})
+
+	return result
+}
+
+// Len returns the number of key value pairs in m.
+func (m *SortedMap[K, V]) Len() int {
+	return m.size
+}
+
+// Max returns the key value pair in m with the largest key, if any.
+func (m *SortedMap[K, V]) Max() (pairs.Pair[K, V], bool) {
+	n := m.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+
+	return n.pair()
+}
+
+// Min returns the key value pair in m with the smallest key, if any.
+func (m *SortedMap[K, V]) Min() (pairs.Pair[K, V], bool) {
+	n := m.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+
+	return n.pair()
+}
+
+// Set makes k map to v in m.
+func (m *SortedMap[K, V]) Set(k K, v V) {
+	var added bool
+	m.root, added = m.insert(m.root, k, v)
+	if added {
+		m.size++
+	}
+}
+
+// ToBatch returns a batch producing the key value pairs in m, in order.
+// Its type matches batches.Batch, to which it can be converted.
+func (m *SortedMap[K, V]) ToBatch() func(func(pairs.Pair[K, V]) bool) {
+	return m.each
+}
+
+// ToSlice returns the key value pairs in m, in order.
+func (m *SortedMap[K, V]) ToSlice() []pairs.Pair[K, V] {
+	result := make([]pairs.Pair[K, V], 0, m.size)
+	m.each(func(kv pairs.Pair[K, V]) bool {
+		result = append(result, kv)
+		return true
+	})
+
+	return result
+}
+
+// Values returns the values in m, in the order of their keys.
+func (m *SortedMap[K, V]) Values() []V {
+	result := make([]V, 0, m.size)
+	m.ForEach(func(_ K, v V) {
+		result = append(result, v)
+	})
+
+	return result
+}
+
+/* Operations */
+
+// Filter creates a new SortedMap with the same ordering as m,
+// containing the key value pairs in m that satisfy the predicate fn.
+func Filter[K, V any](m *SortedMap[K, V], fn func(K, V) bool) *SortedMap[K, V] {
+	result := NewBy[K, V](m.less)
+	m.ForEach(func(k K, v V) {
+		if fn(k, v) {
+			result.Set(k, v)
+		}
+	})
+
+	return result
+}
+
+// Map creates a new SortedMap with the same keys and ordering as m,
+// in which each key maps to the result of applying fn to its key
+// and value in m.
+func Map[K, V, U any](m *SortedMap[K, V], fn func(K, V) U) *SortedMap[K, U] {
+	return &SortedMap[K, U]{
+		root: mapNode(m.root, fn),
+		size: m.size,
+		less: m.less,
+	}
+}
+
+/* Helpers */
+
+// node is a node in the AVL tree backing a SortedMap.
+type node[K, V any] struct {
+	key    K
+	value  V
+	left   *node[K, V]
+	right  *node[K, V]
+	height int
+}
+
+// pair returns the key and value of n, or false if n is nil.
+func (n *node[K, V]) pair() (pairs.Pair[K, V], bool) {
+	if n == nil {
+		return pairs.Pair[K, V]{}, false
+	}
+
+	return pairs.New(n.key, n.value), true
+}
+
+// between appends the key value pairs under n with keys
+// in the range [from, to) to result, in order.
+func (m *SortedMap[K, V]) between(n *node[K, V], from, to K, result *[]pairs.Pair[K, V]) {
+	if n == nil {
+		return
+	}
+
+	aboveFrom := !m.less(n.key, from)
+	belowTo := m.less(n.key, to)
+	if aboveFrom {
+		m.between(n.left, from, to, result)
+	}
+	if aboveFrom && belowTo {
+		*result = append(*result, pairs.New(n.key, n.value))
+	}
+	if belowTo {
+		m.between(n.right, from, to, result)
+	}
+}
+
+// each calls fn on each key value pair in m, in order,
+// stopping early if fn returns false.
+func (m *SortedMap[K, V]) each(fn func(pairs.Pair[K, V]) bool) {
+	var walk func(n *node[K, V]) bool
+	walk = func(n *node[K, V]) bool {
+		if n == nil {
+			return true
+		}
+
+		return walk(n.left) && fn(pairs.New(n.key, n.value)) && walk(n.right)
+	}
+	walk(m.root)
+}
+
+// find returns the node for k, or nil if k is not present.
+func (m *SortedMap[K, V]) find(k K) *node[K, V] {
+	n := m.root
+	for n != nil {
+		switch {
+		case m.less(k, n.key):
+			n = n.left
+		case m.less(n.key, k):
+			n = n.right
+		default:
+			return n
+		}
+	}
+
+	return nil
+}
+
+// insert sets k to v in the tree rooted at n, returning the new root
+// of that tree and whether k was added rather than updated.
+func (m *SortedMap[K, V]) insert(n *node[K, V], k K, v V) (*node[K, V], bool) {
+	if n == nil {
+		return &node[K, V]{key: k, value: v, height: 1}, true
+	}
+
+	var added bool
+	switch {
+	case m.less(k, n.key):
+		n.left, added = m.insert(n.left, k, v)
+	case m.less(n.key, k):
+		n.right, added = m.insert(n.right, k, v)
+	default:
+		n.value = v
+		return n, false
+	}
+
+	return rebalance(n), added
+}
+
+// remove deletes k from the tree rooted at n, returning the new root
+// of that tree and whether k was present.
+func (m *SortedMap[K, V]) remove(n *node[K, V], k K) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var removed bool
+	switch {
+	case m.less(k, n.key):
+		n.left, removed = m.remove(n.left, k)
+	case m.less(n.key, k):
+		n.right, removed = m.remove(n.right, k)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+
+		// Replace n with its successor.
+		var successor *node[K, V]
+		n.right, successor = removeMin(n.right)
+		successor.left = n.left
+		successor.right = n.right
+		n = successor
+		removed = true
+	}
+
+	return rebalance(n), removed
+}
+
+// removeMin detaches the node with the smallest key from the tree
+// rooted at n, returning the new root of that tree and the node.
+func removeMin[K, V any](n *node[K, V]) (*node[K, V], *node[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+
+	var min *node[K, V]
+	n.left, min = removeMin(n.left)
+	return rebalance(n), min
+}
+
+// mapNode copies the tree rooted at n,
+// replacing each value with the result of fn.
+func mapNode[K, V, U any](n *node[K, V], fn func(K, V) U) *node[K, U] {
+	if n == nil {
+		return nil
+	}
+
+	return &node[K, U]{
+		key:    n.key,
+		value:  fn(n.key, n.value),
+		left:   mapNode(n.left, fn),
+		right:  mapNode(n.right, fn),
+		height: n.height,
+	}
+}
+
+// height returns the height of the tree rooted at n.
+func height[K, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+// rebalance restores the AVL invariant at n after one of its
+// subtrees has changed height by one, returning the new root.
+func rebalance[K, V any](n *node[K, V]) *node[K, V] {
+	update(n)
+	switch balance := height(n.left) - height(n.right); {
+	case balance > 1:
+		if height(n.left.left) < height(n.left.right) {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	case balance < -1:
+		if height(n.right.right) < height(n.right.left) {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	default:
+		return n
+	}
+}
+
+// rotateLeft rotates the tree rooted at n to the left,
+// returning the new root.
+func rotateLeft[K, V any](n *node[K, V]) *node[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	update(n)
+	update(r)
+
+	return r
+}
+
+// rotateRight rotates the tree rooted at n to the right,
+// returning the new root.
+func rotateRight[K, V any](n *node[K, V]) *node[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	update(n)
+	update(l)
+
+	return l
+}
+
+// update recomputes the height of n from its children.
+func update[K, V any](n *node[K, V]) {
+	n.height = height(n.left)
+	if h := height(n.right); h > n.height {
+		n.height = h
+	}
+	n.height++
+}
@@ -0,0 +1,359 @@
+package sortedmaps_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcmathja/funky/pairs"
+	"github.com/mcmathja/funky/sortedmaps"
+)
+
+func TestSetAndDelete(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		set    []int
+		delete []int
+		keys   []int
+	}{
+		"ascending inserts": {
+			set:  []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			keys: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		"descending inserts": {
+			set:  []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			keys: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		"zigzag inserts": {
+			set:  []int{5, 1, 9, 2, 8, 3, 7, 4, 6},
+			keys: []int{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		"duplicate inserts": {
+			set:  []int{3, 1, 3, 2, 1},
+			keys: []int{1, 2, 3},
+		},
+		"delete leaves": {
+			set:    []int{4, 2, 6, 1, 3, 5, 7},
+			delete: []int{1, 3, 5, 7},
+			keys:   []int{2, 4, 6},
+		},
+		"delete nodes with two children": {
+			set:    []int{4, 2, 6, 1, 3, 5, 7},
+			delete: []int{4, 2, 6},
+			keys:   []int{1, 3, 5, 7},
+		},
+		"delete ascending": {
+			set:    []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			delete: []int{1, 2, 3, 4, 5, 6},
+			keys:   []int{7, 8, 9, 10},
+		},
+		"delete descending": {
+			set:    []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			delete: []int{10, 9, 8, 7, 6, 5},
+			keys:   []int{1, 2, 3, 4},
+		},
+		"delete everything": {
+			set:    []int{3, 1, 2},
+			delete: []int{1, 2, 3},
+			keys:   []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := sortedmaps.New[int, int]()
+			for _, k := range tc.set {
+				m.Set(k, k*10)
+				if !sortedmaps.Balanced(m) {
+					t.Fatalf("tree unbalanced after setting %d", k)
+				}
+			}
+			for _, k := range tc.delete {
+				if !m.Delete(k) {
+					t.Errorf("expected %d to be present", k)
+				}
+				if !sortedmaps.Balanced(m) {
+					t.Fatalf("tree unbalanced after deleting %d", k)
+				}
+			}
+
+			if keys := m.Keys(); !reflect.DeepEqual(keys, tc.keys) {
+				t.Errorf("expected %+v to equal %+v", keys, tc.keys)
+			}
+			if m.Len() != len(tc.keys) {
+				t.Errorf("expected length %d, but received %d", len(tc.keys), m.Len())
+			}
+			for _, k := range tc.keys {
+				if v, ok := m.Get(k); !ok || v != k*10 {
+					t.Errorf("expected %d, true for key %d, but received %d, %t", k*10, k, v, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestSetAndDeleteLarge(t *testing.T) {
+	t.Parallel()
+
+	m := sortedmaps.New[int, int]()
+	for i := 0; i < 1000; i++ {
+		m.Set((i*7919)%1000, i)
+	}
+	if m.Len() != 1000 || !sortedmaps.Balanced(m) {
+		t.Fatalf("expected a balanced tree of 1000 keys, but received %d keys", m.Len())
+	}
+	for i := 0; i < 1000; i += 2 {
+		m.Delete(i)
+	}
+	if m.Len() != 500 || !sortedmaps.Balanced(m) {
+		t.Fatalf("expected a balanced tree of 500 keys, but received %d keys", m.Len())
+	}
+	if min, _ := m.Min(); min.Left != 1 {
+		t.Errorf("expected a minimum of 1, but received %d", min.Left)
+	}
+	if max, _ := m.Max(); max.Left != 999 {
+		t.Errorf("expected a maximum of 999, but received %d", max.Left)
+	}
+}
+
+func TestDeleteMissing(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		set []int
+		key int
+	}{
+		"empty map": {
+			set: []int{},
+			key: 1,
+		},
+		"below minimum": {
+			set: []int{2, 4, 6},
+			key: 1,
+		},
+		"between keys": {
+			set: []int{2, 4, 6},
+			key: 5,
+		},
+		"above maximum": {
+			set: []int{2, 4, 6},
+			key: 7,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := sortedmaps.New[int, int]()
+			for _, k := range tc.set {
+				m.Set(k, k)
+			}
+
+			if m.Delete(tc.key) {
+				t.Errorf("expected %d to be absent", tc.key)
+			}
+			if m.Len() != len(tc.set) || !sortedmaps.Balanced(m) {
+				t.Errorf("expected the map to be unchanged")
+			}
+		})
+	}
+}
+
+func TestFloorAndCeiling(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		set      []int
+		key      int
+		floor    int
+		hasFloor bool
+		ceiling  int
+		hasCeil  bool
+	}{
+		"exact match": {
+			set:      []int{10, 20, 30},
+			key:      20,
+			floor:    20,
+			hasFloor: true,
+			ceiling:  20,
+			hasCeil:  true,
+		},
+		"between keys": {
+			set:      []int{10, 20, 30},
+			key:      25,
+			floor:    20,
+			hasFloor: true,
+			ceiling:  30,
+			hasCeil:  true,
+		},
+		"below minimum": {
+			set:     []int{10, 20, 30},
+			key:     5,
+			ceiling: 10,
+			hasCeil: true,
+		},
+		"above maximum": {
+			set:      []int{10, 20, 30},
+			key:      35,
+			floor:    30,
+			hasFloor: true,
+		},
+		"empty map": {
+			set: []int{},
+			key: 5,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := sortedmaps.New[int, int]()
+			for _, k := range tc.set {
+				m.Set(k, k)
+			}
+
+			floor, ok := m.Floor(tc.key)
+			if ok != tc.hasFloor || (ok && floor.Left != tc.floor) {
+				t.Errorf("expected floor %d, %t, but received %d, %t", tc.floor, tc.hasFloor, floor.Left, ok)
+			}
+			ceiling, ok := m.Ceiling(tc.key)
+			if ok != tc.hasCeil || (ok && ceiling.Left != tc.ceiling) {
+				t.Errorf("expected ceiling %d, %t, but received %d, %t", tc.ceiling, tc.hasCeil, ceiling.Left, ok)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		set  []int
+		from int
+		to   int
+		keys []int
+	}{
+		"inclusive from and exclusive to": {
+			set:  []int{1, 2, 3, 4, 5},
+			from: 2,
+			to:   4,
+			keys: []int{2, 3},
+		},
+		"bounds between keys": {
+			set:  []int{10, 20, 30, 40},
+			from: 15,
+			to:   35,
+			keys: []int{20, 30},
+		},
+		"whole range": {
+			set:  []int{1, 2, 3},
+			from: 0,
+			to:   10,
+			keys: []int{1, 2, 3},
+		},
+		"equal bounds": {
+			set:  []int{1, 2, 3},
+			from: 2,
+			to:   2,
+			keys: []int{},
+		},
+		"inverted bounds": {
+			set:  []int{1, 2, 3},
+			from: 3,
+			to:   1,
+			keys: []int{},
+		},
+		"no keys in range": {
+			set:  []int{1, 5},
+			from: 2,
+			to:   5,
+			keys: []int{},
+		},
+		"empty map": {
+			set:  []int{},
+			from: 0,
+			to:   10,
+			keys: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			m := sortedmaps.New[int, int]()
+			for _, k := range tc.set {
+				m.Set(k, k)
+			}
+
+			keys := make([]int, 0)
+			for _, kv := range m.Between(tc.from, tc.to) {
+				keys = append(keys, kv.Left)
+			}
+
+			if !reflect.DeepEqual(keys, tc.keys) {
+				t.Errorf("expected %+v to equal %+v", keys, tc.keys)
+			}
+		})
+	}
+}
+
+func TestMinAndMaxEmpty(t *testing.T) {
+	t.Parallel()
+
+	m := sortedmaps.New[int, string]()
+	if _, ok := m.Min(); ok {
+		t.Errorf("expected no minimum for an empty map")
+	}
+	if _, ok := m.Max(); ok {
+		t.Errorf("expected no maximum for an empty map")
+	}
+}
+
+func TestNewBy(t *testing.T) {
+	t.Parallel()
+
+	m := sortedmaps.NewBy(func(a, b int) bool { return a > b }, pairs.New(1, "a"), pairs.New(3, "c"), pairs.New(2, "b"))
+
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []int{3, 2, 1}) {
+		t.Errorf("expected %+v to equal %+v", keys, []int{3, 2, 1})
+	}
+	if values := m.Values(); !reflect.DeepEqual(values, []string{"c", "b", "a"}) {
+		t.Errorf("expected %+v to equal %+v", values, []string{"c", "b", "a"})
+	}
+	if floor, _ := m.Floor(0); floor.Left != 1 {
+		t.Errorf("expected floor 1 in descending order, but received %d", floor.Left)
+	}
+}
+
+func TestFilterAndMap(t *testing.T) {
+	t.Parallel()
+
+	m := sortedmaps.FromMap(map[int]int{1: 1, 2: 4, 3: 9, 4: 16})
+
+	filtered := sortedmaps.Filter(m, func(k, _ int) bool { return k%2 == 0 })
+	if keys := filtered.Keys(); !reflect.DeepEqual(keys, []int{2, 4}) || !sortedmaps.Balanced(filtered) {
+		t.Errorf("expected %+v to equal %+v", keys, []int{2, 4})
+	}
+
+	mapped := sortedmaps.Map(m, func(k, v int) string { return string(rune('a' + v - k)) })
+	if values := mapped.Values(); !reflect.DeepEqual(values, []string{"a", "c", "g", "m"}) {
+		t.Errorf("expected %+v to equal %+v", values, []string{"a", "c", "g", "m"})
+	}
+	if !sortedmaps.Balanced(mapped) {
+		t.Errorf("expected the mapped tree to be balanced")
+	}
+
+	mapped.Set(5, "z")
+	if m.Has(5) {
+		t.Errorf("expected the original map to be unaffected by changes to its mapped copy")
+	}
+}
@@ -0,0 +1,9 @@
+package sortedsets
+
+import "github.com/mcmathja/funky/constraints"
+
+// Backing returns the full backing array of s, including
+// any capacity beyond its elements.
+func Backing[T constraints.Ordered](s *SortedSet[T]) []T {
+	return s.eles[:cap(s.eles)]
+}
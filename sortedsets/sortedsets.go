@@ -0,0 +1,355 @@
+// sortedsets provides a set type that keeps its elements sorted, along
+// with generic convenience functions for working with it. Unlike a plain
+// set, operations that depend on order, such as Take and TakeWhile,
+// have well-defined results.
+package sortedsets
+
+import (
+	"sort"
+
+	"github.com/mcmathja/funky/constraints"
+)
+
+// SortedSet is a set that keeps its elements in ascending order,
+// backed by a sorted slice. Lookups, Floor and Ceiling take logarithmic
+// time and Min and Max take constant time, while Add and Remove take
+// linear time. Set operations such as Union merge their inputs
+// in linear time. The zero value is an empty SortedSet ready to use.
+// A SortedSet is not safe for concurrent use.
+type SortedSet[T constraints.Ordered] struct {
+	// eles holds the elements in ascending order, without duplicates.
+	eles []T
+}
+
+/* Constructors */
+
+// FromSet creates a new SortedSet containing the elements in s.
+func FromSet[T constraints.Ordered](s map[T]struct{}) *SortedSet[T] {
+	eles := make([]T, 0, len(s))
+	for ele := range s {
+		eles = append(eles, ele)
+	}
+
+	return fromUnsorted(eles)
+}
+
+// FromSlice creates a new SortedSet containing the elements in s.
+func FromSlice[T constraints.Ordered](s []T) *SortedSet[T] {
+	return fromUnsorted(append([]T{}, s...))
+}
+
+// New creates a new SortedSet containing the provided elements.
+func New[T constraints.Ordered](eles ...T) *SortedSet[T] {
+	return FromSlice(eles)
+}
+
+/* Methods */
+
+// Add inserts ele into s, reporting whether it was not already present.
+func (s *SortedSet[T]) Add(ele T) bool {
+	idx, found := s.search(ele)
+	if found {
+		return false
+	}
+
+	var zero T
+	s.eles = append(s.eles, zero)
+	copy(s.eles[idx+1:], s.eles[idx:])
+	s.eles[idx] = ele
+
+	return true
+}
+
+// Between returns the elements in s between
+// from (inclusive) and to (exclusive), in order.
+func (s *SortedSet[T]) Between(from, to T) []T {
+	lo, _ := s.search(from)
+	hi, _ := s.search(to)
+	if hi < lo {
+		hi = lo
+	}
+
+	return append([]T{}, s.eles[lo:hi]...)
+}
+
+// Ceiling returns the smallest element in s
+// greater than or equal to ele, if any.
+func (s *SortedSet[T]) Ceiling(ele T) (T, bool) {
+	idx, _ := s.search(ele)
+	return s.at(idx)
+}
+
+// Contains checks whether ele is in s.
+func (s *SortedSet[T]) Contains(ele T) bool {
+	_, found := s.search(ele)
+	return found
+}
+
+// Floor returns the largest element in s
+// less than or equal to ele, if any.
+func (s *SortedSet[T]) Floor(ele T) (T, bool) {
+	idx, found := s.search(ele)
+	if found {
+		return s.at(idx)
+	}
+
+	return s.at(idx - 1)
+}
+
+// ForEach calls fn on each element in s, in order.
+func (s *SortedSet[T]) ForEach(fn func(T)) {
+	for _, ele := range s.eles {
+		fn(ele)
+	}
+}
+
+// Len returns the number of elements in s.
+func (s *SortedSet[T]) Len() int {
+	return len(s.eles)
+}
+
+// Max returns the largest element in s, if any.
+func (s *SortedSet[T]) Max() (T, bool) {
+	return s.at(len(s.eles) - 1)
+}
+
+// Min returns the smallest element in s, if any.
+func (s *SortedSet[T]) Min() (T, bool) {
+	return s.at(0)
+}
+
+// Remove deletes ele from s, reporting whether it was present.
+func (s *SortedSet[T]) Remove(ele T) bool {
+	idx, found := s.search(ele)
+	if !found {
+		return false
+	}
+
+	copy(s.eles[idx:], s.eles[idx+1:])
+	var zero T
+	s.eles[len(s.eles)-1] = zero
+	s.eles = s.eles[:len(s.eles)-1]
+
+	return true
+}
+
+// ToBatch returns a batch producing the elements in s, in order.
+// Its type matches batches.Batch, to which it can be converted.
+func (s *SortedSet[T]) ToBatch() func(func(T) bool) {
+	return func(fn func(T) bool) {
+		for _, ele := range s.eles {
+			if !fn(ele) {
+				return
+			}
+		}
+	}
+}
+
+// ToSet returns a plain set containing the elements in s.
+func (s *SortedSet[T]) ToSet() map[T]struct{} {
+	result := make(map[T]struct{}, len(s.eles))
+	for _, ele := range s.eles {
+		result[ele] = struct{}{}
+	}
+
+	return result
+}
+
+// ToSlice returns the elements in s, in order.
+func (s *SortedSet[T]) ToSlice() []T {
+	return append([]T{}, s.eles...)
+}
+
+/* Operations */
+
+// Difference creates a new SortedSet containing
+// the elements in a that are not in b.
+func Difference[T constraints.Ordered](a, b *SortedSet[T]) *SortedSet[T] {
+	result := make([]T, 0, len(a.eles))
+	i, j := 0, 0
+	for i < len(a.eles) {
+		switch {
+		case j >= len(b.eles) || a.eles[i] < b.eles[j]:
+			result = append(result, a.eles[i])
+			i++
+		case b.eles[j] < a.eles[i]:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+
+	return &SortedSet[T]{eles: result}
+}
+
+// Drop creates a new SortedSet containing the elements
+// in s except for the num smallest ones.
+func Drop[T constraints.Ordered](s *SortedSet[T], num int) *SortedSet[T] {
+	if num < 0 {
+		num = 0
+	}
+	if num > len(s.eles) {
+		num = len(s.eles)
+	}
+
+	return &SortedSet[T]{eles: append([]T{}, s.eles[num:]...)}
+}
+
+// DropWhile creates a new SortedSet containing the elements in s
+// except for those visited in order before the first one
+// that does not satisfy fn.
+func DropWhile[T constraints.Ordered](s *SortedSet[T], fn func(T) bool) *SortedSet[T] {
+	idx := 0
+	for idx < len(s.eles) && fn(s.eles[idx]) {
+		idx++
+	}
+
+	return Drop(s, idx)
+}
+
+// Equals checks whether a and b contain the same elements.
+func Equals[T constraints.Ordered](a, b *SortedSet[T]) bool {
+	if len(a.eles) != len(b.eles) {
+		return false
+	}
+
+	for idx := range a.eles {
+		if a.eles[idx] != b.eles[idx] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter creates a new SortedSet containing
+// the elements in s that satisfy the predicate fn.
+func Filter[T constraints.Ordered](s *SortedSet[T], fn func(T) bool) *SortedSet[T] {
+	result := make([]T, 0)
+	for _, ele := range s.eles {
+		if fn(ele) {
+			result = append(result, ele)
+		}
+	}
+
+	return &SortedSet[T]{eles: result}
+}
+
+// Intersect creates a new SortedSet containing
+// the elements that are in every one of ss.
+func Intersect[T constraints.Ordered](ss ...*SortedSet[T]) *SortedSet[T] {
+	if len(ss) == 0 {
+		return &SortedSet[T]{}
+	}
+
+	result := ss[0].eles
+	for _, s := range ss[1:] {
+		next := make([]T, 0)
+		i, j := 0, 0
+		for i < len(result) && j < len(s.eles) {
+			switch {
+			case result[i] < s.eles[j]:
+				i++
+			case s.eles[j] < result[i]:
+				j++
+			default:
+				next = append(next, result[i])
+				i++
+				j++
+			}
+		}
+		result = next
+	}
+
+	return &SortedSet[T]{eles: append([]T{}, result...)}
+}
+
+// Take creates a new SortedSet containing the num smallest elements in s.
+func Take[T constraints.Ordered](s *SortedSet[T], num int) *SortedSet[T] {
+	if num < 0 {
+		num = 0
+	}
+	if num > len(s.eles) {
+		num = len(s.eles)
+	}
+
+	return &SortedSet[T]{eles: append([]T{}, s.eles[:num]...)}
+}
+
+// TakeWhile creates a new SortedSet containing the elements in s
+// visited in order before the first one that does not satisfy fn.
+func TakeWhile[T constraints.Ordered](s *SortedSet[T], fn func(T) bool) *SortedSet[T] {
+	idx := 0
+	for idx < len(s.eles) && fn(s.eles[idx]) {
+		idx++
+	}
+
+	return Take(s, idx)
+}
+
+// Union creates a new SortedSet containing
+// the elements that are in any one of ss.
+func Union[T constraints.Ordered](ss ...*SortedSet[T]) *SortedSet[T] {
+	result := make([]T, 0)
+	for _, s := range ss {
+		next := make([]T, 0, len(result)+len(s.eles))
+		i, j := 0, 0
+		for i < len(result) || j < len(s.eles) {
+			switch {
+			case j >= len(s.eles) || (i < len(result) && result[i] < s.eles[j]):
+				next = append(next, result[i])
+				i++
+			case i >= len(result) || s.eles[j] < result[i]:
+				next = append(next, s.eles[j])
+				j++
+			default:
+				next = append(next, result[i])
+				i++
+				j++
+			}
+		}
+		result = next
+	}
+
+	return &SortedSet[T]{eles: result}
+}
+
+/* Helpers */
+
+// fromUnsorted creates a new SortedSet that takes ownership of eles,
+// sorting them and removing any duplicates.
+func fromUnsorted[T constraints.Ordered](eles []T) *SortedSet[T] {
+	sort.Slice(eles, func(i, j int) bool {
+		return eles[i] < eles[j]
+	})
+
+	result := eles[:0]
+	for idx, ele := range eles {
+		if idx == 0 || result[len(result)-1] != ele {
+			result = append(result, ele)
+		}
+	}
+
+	return &SortedSet[T]{eles: result}
+}
+
+// at returns the element at idx in s, if idx is in range.
+func (s *SortedSet[T]) at(idx int) (T, bool) {
+	if idx < 0 || idx >= len(s.eles) {
+		var zero T
+		return zero, false
+	}
+
+	return s.eles[idx], true
+}
+
+// search returns the index of the first element in s that is
+// not less than ele, and whether that element equals ele.
+func (s *SortedSet[T]) search(ele T) (int, bool) {
+	idx := sort.Search(len(s.eles), func(i int) bool {
+		return !(s.eles[i] < ele)
+	})
+
+	return idx, idx < len(s.eles) && s.eles[idx] == ele
+}
@@ -0,0 +1,449 @@
+package sortedsets_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcmathja/funky/sortedsets"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in  []int
+		out []int
+	}{
+		"simple case": {
+			in:  []int{3, 1, 2},
+			out: []int{1, 2, 3},
+		},
+		"duplicate elements": {
+			in:  []int{2, 1, 2, 3, 1},
+			out: []int{1, 2, 3},
+		},
+		"empty input": {
+			in:  []int{},
+			out: []int{},
+		},
+		"nil input": {
+			in:  nil,
+			out: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := sortedsets.New(tc.in...).ToSlice()
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in    []int
+		ele   int
+		added bool
+		out   []int
+	}{
+		"into the middle": {
+			in:    []int{1, 3},
+			ele:   2,
+			added: true,
+			out:   []int{1, 2, 3},
+		},
+		"at the start": {
+			in:    []int{2, 3},
+			ele:   1,
+			added: true,
+			out:   []int{1, 2, 3},
+		},
+		"at the end": {
+			in:    []int{1, 2},
+			ele:   3,
+			added: true,
+			out:   []int{1, 2, 3},
+		},
+		"existing element": {
+			in:    []int{1, 2, 3},
+			ele:   2,
+			added: false,
+			out:   []int{1, 2, 3},
+		},
+		"empty set": {
+			in:    []int{},
+			ele:   1,
+			added: true,
+			out:   []int{1},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := sortedsets.New(tc.in...)
+			added := s.Add(tc.ele)
+
+			if added != tc.added {
+				t.Errorf("expected %t, but received %t", tc.added, added)
+			}
+			if out := s.ToSlice(); !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in      []int
+		ele     int
+		removed bool
+		out     []int
+	}{
+		"from the middle": {
+			in:      []int{1, 2, 3},
+			ele:     2,
+			removed: true,
+			out:     []int{1, 3},
+		},
+		"from the start": {
+			in:      []int{1, 2, 3},
+			ele:     1,
+			removed: true,
+			out:     []int{2, 3},
+		},
+		"from the end": {
+			in:      []int{1, 2, 3},
+			ele:     3,
+			removed: true,
+			out:     []int{1, 2},
+		},
+		"missing element": {
+			in:      []int{1, 3},
+			ele:     2,
+			removed: false,
+			out:     []int{1, 3},
+		},
+		"empty set": {
+			in:      []int{},
+			ele:     1,
+			removed: false,
+			out:     []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := sortedsets.New(tc.in...)
+			removed := s.Remove(tc.ele)
+
+			if removed != tc.removed {
+				t.Errorf("expected %t, but received %t", tc.removed, removed)
+			}
+			if out := s.ToSlice(); !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestRemoveClearsElement(t *testing.T) {
+	t.Parallel()
+
+	s := sortedsets.New("a", "b", "c")
+	s.Remove("a")
+
+	backing := sortedsets.Backing(s)
+	if !reflect.DeepEqual(backing[:2], []string{"b", "c"}) {
+		t.Errorf(`expected %+v to equal %+v`, backing[:2], []string{"b", "c"})
+	}
+	for _, ele := range backing[2:] {
+		if ele != "" {
+			t.Errorf("expected the removed slot to be cleared, but found %q", ele)
+		}
+	}
+}
+
+func TestFloorAndCeiling(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in       []int
+		ele      int
+		floor    int
+		hasFloor bool
+		ceiling  int
+		hasCeil  bool
+	}{
+		"exact match": {
+			in:       []int{10, 20, 30},
+			ele:      20,
+			floor:    20,
+			hasFloor: true,
+			ceiling:  20,
+			hasCeil:  true,
+		},
+		"between elements": {
+			in:       []int{10, 20, 30},
+			ele:      25,
+			floor:    20,
+			hasFloor: true,
+			ceiling:  30,
+			hasCeil:  true,
+		},
+		"below minimum": {
+			in:      []int{10, 20, 30},
+			ele:     5,
+			ceiling: 10,
+			hasCeil: true,
+		},
+		"above maximum": {
+			in:       []int{10, 20, 30},
+			ele:      35,
+			floor:    30,
+			hasFloor: true,
+		},
+		"empty set": {
+			in:  []int{},
+			ele: 5,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := sortedsets.New(tc.in...)
+
+			floor, ok := s.Floor(tc.ele)
+			if ok != tc.hasFloor || floor != tc.floor {
+				t.Errorf("expected floor %d, %t, but received %d, %t", tc.floor, tc.hasFloor, floor, ok)
+			}
+			ceiling, ok := s.Ceiling(tc.ele)
+			if ok != tc.hasCeil || ceiling != tc.ceiling {
+				t.Errorf("expected ceiling %d, %t, but received %d, %t", tc.ceiling, tc.hasCeil, ceiling, ok)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in   []int
+		from int
+		to   int
+		out  []int
+	}{
+		"inclusive from and exclusive to": {
+			in:   []int{1, 2, 3, 4, 5},
+			from: 2,
+			to:   4,
+			out:  []int{2, 3},
+		},
+		"bounds between elements": {
+			in:   []int{10, 20, 30, 40},
+			from: 15,
+			to:   35,
+			out:  []int{20, 30},
+		},
+		"equal bounds": {
+			in:   []int{1, 2, 3},
+			from: 2,
+			to:   2,
+			out:  []int{},
+		},
+		"inverted bounds": {
+			in:   []int{1, 2, 3},
+			from: 3,
+			to:   1,
+			out:  []int{},
+		},
+		"empty set": {
+			in:   []int{},
+			from: 0,
+			to:   10,
+			out:  []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			out := sortedsets.New(tc.in...).Between(tc.from, tc.to)
+
+			if !reflect.DeepEqual(out, tc.out) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.out)
+			}
+		})
+	}
+}
+
+func TestMinAndMax(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in     []int
+		min    int
+		max    int
+		exists bool
+	}{
+		"simple case": {
+			in:     []int{3, 1, 2},
+			min:    1,
+			max:    3,
+			exists: true,
+		},
+		"single element": {
+			in:     []int{5},
+			min:    5,
+			max:    5,
+			exists: true,
+		},
+		"empty set": {
+			in:     []int{},
+			exists: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := sortedsets.New(tc.in...)
+
+			if min, ok := s.Min(); ok != tc.exists || min != tc.min {
+				t.Errorf("expected min %d, %t, but received %d, %t", tc.min, tc.exists, min, ok)
+			}
+			if max, ok := s.Max(); ok != tc.exists || max != tc.max {
+				t.Errorf("expected max %d, %t, but received %d, %t", tc.max, tc.exists, max, ok)
+			}
+		})
+	}
+}
+
+func TestAlgebra(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in         [][]int
+		union      []int
+		intersect  []int
+		difference []int
+	}{
+		"overlapping sets": {
+			in:         [][]int{{1, 2, 3, 4}, {3, 4, 5}},
+			union:      []int{1, 2, 3, 4, 5},
+			intersect:  []int{3, 4},
+			difference: []int{1, 2},
+		},
+		"three sets": {
+			in:         [][]int{{1, 2, 3, 4}, {3, 4, 5}, {4, 5, 6}},
+			union:      []int{1, 2, 3, 4, 5, 6},
+			intersect:  []int{4},
+			difference: []int{1, 2},
+		},
+		"disjoint sets": {
+			in:         [][]int{{1, 3}, {2, 4}},
+			union:      []int{1, 2, 3, 4},
+			intersect:  []int{},
+			difference: []int{1, 3},
+		},
+		"identical sets": {
+			in:         [][]int{{1, 2}, {1, 2}},
+			union:      []int{1, 2},
+			intersect:  []int{1, 2},
+			difference: []int{},
+		},
+		"empty second set": {
+			in:         [][]int{{1, 2}, {}},
+			union:      []int{1, 2},
+			intersect:  []int{},
+			difference: []int{1, 2},
+		},
+		"empty first set": {
+			in:         [][]int{{}, {1, 2}},
+			union:      []int{1, 2},
+			intersect:  []int{},
+			difference: []int{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ss := make([]*sortedsets.SortedSet[int], 0, len(tc.in))
+			for _, eles := range tc.in {
+				ss = append(ss, sortedsets.New(eles...))
+			}
+
+			if out := sortedsets.Union(ss...).ToSlice(); !reflect.DeepEqual(out, tc.union) {
+				t.Errorf(`expected union %+v to equal %+v`, out, tc.union)
+			}
+			if out := sortedsets.Intersect(ss...).ToSlice(); !reflect.DeepEqual(out, tc.intersect) {
+				t.Errorf(`expected intersection %+v to equal %+v`, out, tc.intersect)
+			}
+			if out := sortedsets.Difference(ss[0], ss[1]).ToSlice(); !reflect.DeepEqual(out, tc.difference) {
+				t.Errorf(`expected difference %+v to equal %+v`, out, tc.difference)
+			}
+		})
+	}
+}
+
+func TestTakeAndDrop(t *testing.T) {
+	t.Parallel()
+
+	s := sortedsets.New(4, 1, 3, 2)
+	lt3 := func(i int) bool { return i < 3 }
+
+	testCases := map[string]struct {
+		out  *sortedsets.SortedSet[int]
+		want []int
+	}{
+		"Take":               {out: sortedsets.Take(s, 2), want: []int{1, 2}},
+		"Take too many":      {out: sortedsets.Take(s, 9), want: []int{1, 2, 3, 4}},
+		"Take negative":      {out: sortedsets.Take(s, -1), want: []int{}},
+		"Drop":               {out: sortedsets.Drop(s, 3), want: []int{4}},
+		"Drop too many":      {out: sortedsets.Drop(s, 9), want: []int{}},
+		"Drop negative":      {out: sortedsets.Drop(s, -1), want: []int{1, 2, 3, 4}},
+		"TakeWhile":          {out: sortedsets.TakeWhile(s, lt3), want: []int{1, 2}},
+		"DropWhile":          {out: sortedsets.DropWhile(s, lt3), want: []int{3, 4}},
+		"Filter":             {out: sortedsets.Filter(s, func(i int) bool { return i%2 == 0 }), want: []int{2, 4}},
+		"FromSet round trip": {out: sortedsets.FromSet(s.ToSet()), want: []int{1, 2, 3, 4}},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if out := tc.out.ToSlice(); !reflect.DeepEqual(out, tc.want) {
+				t.Errorf(`expected %+v to equal %+v`, out, tc.want)
+			}
+		})
+	}
+}